@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"emperror.dev/errors"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// MaxLastAppliedConfigurationSize is the max size of annotations used by client-side apply
+	MaxLastAppliedConfigurationSize = 262144
+)
+
+// CRDAnalysis is the size analysis of CRD
+// The sizes are in bytes of JSON, like the annotation 'kubectl.kubernetes.io/last-applied-configuration'
+type CRDAnalysis struct {
+	Name            string
+	Size            int
+	VersionSizes    map[string]int
+	HeavyProperties []PropertySize
+	CleanCandidates []PropertySize
+}
+
+// PropertySize is the size of property schema
+type PropertySize struct {
+	Version string
+	Path    string
+	Size    int
+}
+
+// loadCRD permit to read CRD from file
+func loadCRD(file string) (crd *apiv1.CustomResourceDefinition, err error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error when read file %s", file)
+	}
+	crd = &apiv1.CustomResourceDefinition{}
+	if err = yaml.Unmarshal(b, crd); err != nil {
+		return nil, errors.Wrapf(err, "Error when decode CRD from file %s", file)
+	}
+
+	return crd, nil
+}
+
+// AnalyzeCrd permit to report the size of CRD files, the heavy properties and the '@clean' candidates
+func AnalyzeCrd(c *cli.Context) error {
+	fileMatches, err := filepath.Glob(c.String("crd-file"))
+	if err != nil {
+		return errors.Wrapf(err, "Error when search files from %s", c.String("crd-file"))
+	}
+
+	for _, file := range fileMatches {
+		crd, err := loadCRD(file)
+		if err != nil {
+			return err
+		}
+
+		analysis, err := AnalyzeCRD(crd, c.Int("top"), c.Int("clean-threshold"))
+		if err != nil {
+			return errors.Wrapf(err, "Error when analyze CRD from file %s", file)
+		}
+
+		printCRDAnalysis(c.App.Writer, file, analysis)
+		if analysis.Size > MaxLastAppliedConfigurationSize {
+			log.Warnf("CRD %s is bigger than %d bytes, it can't be applied with client-side apply", analysis.Name, MaxLastAppliedConfigurationSize)
+		}
+	}
+
+	return nil
+}
+
+// AnalyzeCRD permit to compute the size of CRD and of its properties
+// It keep the top heavy properties, and suggest as '@clean' candidates the top-most properties bigger than cleanThreshold
+func AnalyzeCRD(crd *apiv1.CustomResourceDefinition, top int, cleanThreshold int) (analysis *CRDAnalysis, err error) {
+	analysis = &CRDAnalysis{
+		Name:         crd.Name,
+		VersionSizes: make(map[string]int, len(crd.Spec.Versions)),
+	}
+
+	if analysis.Size, err = jsonSize(crd); err != nil {
+		return nil, err
+	}
+
+	properties := make([]PropertySize, 0)
+	for _, version := range crd.Spec.Versions {
+		if analysis.VersionSizes[version.Name], err = jsonSize(version); err != nil {
+			return nil, err
+		}
+	}
+
+	sizes := map[string]int{}
+	helper.WalkCRD(crd, func(version string, path string, props *apiv1.JSONSchemaProps) bool {
+		if err != nil {
+			return false
+		}
+		if path == "" {
+			return true
+		}
+		size, errSize := jsonSize(props)
+		if errSize != nil {
+			err = errSize
+			return false
+		}
+		properties = append(properties, PropertySize{Version: version, Path: path, Size: size})
+		sizes[version+"/"+path] = size
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(properties, func(i, j int) bool {
+		return properties[i].Size > properties[j].Size
+	})
+
+	// Clean candidates are the top-most properties that are bigger than threshold
+	// The root of spec and status are not candidates
+	if cleanThreshold > 0 {
+		helper.WalkCRD(crd, func(version string, path string, props *apiv1.JSONSchemaProps) bool {
+			if path == "" || path == "spec" || path == "status" {
+				return true
+			}
+			if props.XPreserveUnknownFields != nil && *props.XPreserveUnknownFields {
+				return false
+			}
+			if size := sizes[version+"/"+path]; size >= cleanThreshold {
+				analysis.CleanCandidates = append(analysis.CleanCandidates, PropertySize{Version: version, Path: path, Size: size})
+				return false
+			}
+
+			return true
+		})
+	}
+
+	if top > 0 && len(properties) > top {
+		properties = properties[:top]
+	}
+	analysis.HeavyProperties = properties
+
+	return analysis, nil
+}
+
+// printCRDAnalysis permit to print the analysis report
+func printCRDAnalysis(w io.Writer, file string, analysis *CRDAnalysis) {
+	fmt.Fprintf(w, "CRD %s (%s): %d bytes\n", analysis.Name, file, analysis.Size)
+
+	versions := make([]string, 0, len(analysis.VersionSizes))
+	for version := range analysis.VersionSizes {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	for _, version := range versions {
+		fmt.Fprintf(w, "  Version %s: %d bytes\n", version, analysis.VersionSizes[version])
+	}
+
+	fmt.Fprintln(w, "  Heavy properties:")
+	for _, property := range analysis.HeavyProperties {
+		fmt.Fprintf(w, "    %s %s: %d bytes\n", property.Version, property.Path, property.Size)
+	}
+
+	if len(analysis.CleanCandidates) > 0 {
+		fmt.Fprintln(w, "  Candidates to '@clean':")
+		for _, property := range analysis.CleanCandidates {
+			fmt.Fprintf(w, "    %s %s: %d bytes\n", property.Version, property.Path, property.Size)
+		}
+	}
+}
+
+func jsonSize(o any) (size int, err error) {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return 0, errors.Wrap(err, "Error when encode to JSON")
+	}
+
+	return len(b), nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestAnalyzeCRD(t *testing.T) {
+	crd := &apiv1.CustomResourceDefinition{}
+	require.NoError(t, yaml.Unmarshal([]byte(testProcessCrd), crd))
+
+	analysis, err := AnalyzeCRD(crd, 3, 150)
+	require.NoError(t, err)
+	assert.Equal(t, "roles.test.example.com", analysis.Name)
+	assert.Greater(t, analysis.Size, analysis.VersionSizes["v1"])
+	assert.Greater(t, analysis.VersionSizes["v1"], analysis.VersionSizes["v1alpha1"])
+	require.Len(t, analysis.HeavyProperties, 3)
+	assert.Equal(t, "spec", analysis.HeavyProperties[0].Path)
+	assert.GreaterOrEqual(t, analysis.HeavyProperties[1].Size, analysis.HeavyProperties[2].Size)
+
+	// spec.users is the top-most property bigger than threshold, so its children are not candidates
+	paths := make([]string, 0, len(analysis.CleanCandidates))
+	for _, candidate := range analysis.CleanCandidates {
+		paths = append(paths, candidate.Path)
+	}
+	assert.Contains(t, paths, "spec.users")
+	assert.NotContains(t, paths, "spec")
+	assert.NotContains(t, paths, "spec.users[]")
+
+	buf := new(bytes.Buffer)
+	printCRDAnalysis(buf, "crd.yaml", analysis)
+	assert.Contains(t, buf.String(), "CRD roles.test.example.com (crd.yaml)")
+	assert.Contains(t, buf.String(), "Candidates to '@clean':")
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// CRDChange is a schema change between two CRD revisions
+type CRDChange struct {
+	Version    string
+	Path       string
+	Message    string
+	IsBreaking bool
+}
+
+func (h CRDChange) String() string {
+	level := "info"
+	if h.IsBreaking {
+		level = "breaking"
+	}
+	if h.Path == "" {
+		return fmt.Sprintf("[%s] %s: %s", level, h.Version, h.Message)
+	}
+
+	return fmt.Sprintf("[%s] %s %s: %s", level, h.Version, h.Path, h.Message)
+}
+
+// CompareCrd permit to compare two CRD revisions
+// It exit with code 1 when there are breaking changes
+func CompareCrd(c *cli.Context) error {
+	oldCrd, err := loadCRD(c.String("old-crd-file"))
+	if err != nil {
+		return err
+	}
+	newCrd, err := loadCRD(c.String("new-crd-file"))
+	if err != nil {
+		return err
+	}
+
+	changes := CompareCRD(oldCrd, newCrd)
+	nbBreaking := 0
+	for _, change := range changes {
+		fmt.Fprintln(c.App.Writer, change.String())
+		if change.IsBreaking {
+			nbBreaking++
+		}
+	}
+
+	if nbBreaking > 0 {
+		return cli.Exit(fmt.Sprintf("Found %d breaking changes", nbBreaking), 1)
+	}
+
+	log.Info("No breaking change found")
+
+	return nil
+}
+
+// CompareCRD permit to compute the schema changes between old and new CRD
+// Removed versions, removed fields, type changes and new required fields are breaking
+func CompareCRD(oldCrd, newCrd *apiv1.CustomResourceDefinition) (changes []CRDChange) {
+	changes = make([]CRDChange, 0)
+	newVersions := make(map[string]apiv1.CustomResourceDefinitionVersion, len(newCrd.Spec.Versions))
+	for _, version := range newCrd.Spec.Versions {
+		newVersions[version.Name] = version
+	}
+
+	for _, oldVersion := range oldCrd.Spec.Versions {
+		newVersion, isFound := newVersions[oldVersion.Name]
+		if !isFound {
+			changes = append(changes, CRDChange{Version: oldVersion.Name, Message: "version removed", IsBreaking: oldVersion.Served})
+			continue
+		}
+		if oldVersion.Served && !newVersion.Served {
+			changes = append(changes, CRDChange{Version: oldVersion.Name, Message: "version not served anymore", IsBreaking: true})
+		}
+		if oldVersion.Schema == nil || newVersion.Schema == nil {
+			continue
+		}
+
+		oldProperties := flattenSchema(oldVersion.Schema.OpenAPIV3Schema)
+		newProperties := flattenSchema(newVersion.Schema.OpenAPIV3Schema)
+
+		// Removed fields and type changes
+		removedPaths := make([]string, 0)
+		for _, path := range sortedKeys(oldProperties) {
+			oldProps := oldProperties[path]
+			newProps, isFound := newProperties[path]
+			if !isFound {
+				if isChildOf(path, removedPaths) || isUnderPreserveUnknownFields(path, newProperties) {
+					continue
+				}
+				removedPaths = append(removedPaths, path)
+				changes = append(changes, CRDChange{Version: oldVersion.Name, Path: path, Message: "field removed", IsBreaking: true})
+				continue
+			}
+			if oldProps.Type != newProps.Type && oldProps.Type != "" && newProps.Type != "" {
+				changes = append(changes, CRDChange{Version: oldVersion.Name, Path: path, Message: fmt.Sprintf("type changed from %s to %s", oldProps.Type, newProps.Type), IsBreaking: true})
+			}
+		}
+
+		// New fields and new required fields
+		for _, path := range sortedKeys(newProperties) {
+			newProps := newProperties[path]
+			oldProps, isFound := oldProperties[path]
+			if !isFound {
+				changes = append(changes, CRDChange{Version: oldVersion.Name, Path: path, Message: "field added"})
+				continue
+			}
+			oldRequired := make(map[string]bool, len(oldProps.Required))
+			for _, required := range oldProps.Required {
+				oldRequired[required] = true
+			}
+			for _, required := range newProps.Required {
+				if !oldRequired[required] {
+					changes = append(changes, CRDChange{Version: oldVersion.Name, Path: helper.JoinSchemaPath(path, required), Message: "field is now required", IsBreaking: true})
+				}
+			}
+		}
+	}
+
+	for _, newVersion := range newCrd.Spec.Versions {
+		isFound := false
+		for _, oldVersion := range oldCrd.Spec.Versions {
+			if oldVersion.Name == newVersion.Name {
+				isFound = true
+				break
+			}
+		}
+		if !isFound {
+			changes = append(changes, CRDChange{Version: newVersion.Name, Message: "version added"})
+		}
+	}
+
+	return changes
+}
+
+// flattenSchema permit to get all schema nodes by path
+func flattenSchema(props *apiv1.JSONSchemaProps) map[string]*apiv1.JSONSchemaProps {
+	properties := map[string]*apiv1.JSONSchemaProps{}
+	helper.WalkSchema(props.DeepCopy(), "", func(path string, props *apiv1.JSONSchemaProps) bool {
+		if _, isFound := properties[path]; !isFound {
+			properties[path] = props.DeepCopy()
+		}
+		return true
+	})
+
+	return properties
+}
+
+// isChildOf permit to check if path is a child of one of parent paths
+func isChildOf(path string, parentPaths []string) bool {
+	for _, parentPath := range parentPaths {
+		if strings.HasPrefix(path, parentPath+".") || strings.HasPrefix(path, parentPath+"[]") || strings.HasPrefix(path, parentPath+"{}") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isUnderPreserveUnknownFields permit to check if the path is under a field that preserve unknown fields, so the field is not really removed
+func isUnderPreserveUnknownFields(path string, properties map[string]*apiv1.JSONSchemaProps) bool {
+	for parentPath, props := range properties {
+		if props.XPreserveUnknownFields != nil && *props.XPreserveUnknownFields && (parentPath == "" || isChildOf(path, []string{parentPath})) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortedKeys(properties map[string]*apiv1.JSONSchemaProps) []string {
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestCompareCRD(t *testing.T) {
+	oldCrd := &apiv1.CustomResourceDefinition{}
+	require.NoError(t, yaml.Unmarshal([]byte(testProcessCrd), oldCrd))
+
+	// When same CRD
+	assert.Empty(t, CompareCRD(oldCrd, oldCrd.DeepCopy()))
+
+	// When breaking changes
+	newCrd := oldCrd.DeepCopy()
+	newCrd.Spec.Versions = newCrd.Spec.Versions[1:]
+	spec := newCrd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"]
+	delete(spec.Properties, "users")
+	replicas := spec.Properties["replicas"]
+	replicas.Type = "string"
+	spec.Properties["replicas"] = replicas
+	spec.Properties["labels"] = apiv1.JSONSchemaProps{Type: "object"}
+	spec.Required = []string{"name"}
+	newCrd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"] = spec
+
+	changes := CompareCRD(oldCrd, newCrd)
+	assert.ElementsMatch(t, []CRDChange{
+		{Version: "v1alpha1", Message: "version removed", IsBreaking: true},
+		{Version: "v1", Path: "spec.users", Message: "field removed", IsBreaking: true},
+		{Version: "v1", Path: "spec.replicas", Message: "type changed from integer to string", IsBreaking: true},
+		{Version: "v1", Path: "spec.name", Message: "field is now required", IsBreaking: true},
+		{Version: "v1", Path: "spec.labels", Message: "field added"},
+	}, changes)
+
+	// When fields are removed because of parent preserve unknown fields
+	newCrd = oldCrd.DeepCopy()
+	spec = newCrd.Spec.Versions[1].Schema.OpenAPIV3Schema.Properties["spec"]
+	cleanSchema(&spec)
+	newCrd.Spec.Versions[1].Schema.OpenAPIV3Schema.Properties["spec"] = spec
+	assert.Empty(t, CompareCRD(oldCrd, newCrd))
+}
+
+func TestCompareCrd(t *testing.T) {
+	dir := t.TempDir()
+	oldFile := filepath.Join(dir, "old.yaml")
+	newFile := filepath.Join(dir, "new.yaml")
+	require.NoError(t, os.WriteFile(oldFile, []byte(testProcessCrd), 0644))
+
+	exitCode := 0
+	cli.OsExiter = func(code int) { exitCode = code }
+	defer func() { cli.OsExiter = os.Exit }()
+
+	// When no breaking change
+	require.NoError(t, os.WriteFile(newFile, []byte(testProcessCrd), 0644))
+	assert.NoError(t, run([]string{"crd", "--no-color", "compare-crd", "--old-crd-file", oldFile, "--new-crd-file", newFile}))
+	assert.Equal(t, 0, exitCode)
+
+	// When breaking change
+	crd := &apiv1.CustomResourceDefinition{}
+	require.NoError(t, yaml.Unmarshal([]byte(testProcessCrd), crd))
+	crd.Spec.Versions = crd.Spec.Versions[1:]
+	b, err := yaml.Marshal(crd)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(newFile, b, 0644))
+	assert.Error(t, run([]string{"crd", "--no-color", "compare-crd", "--old-crd-file", oldFile, "--new-crd-file", newFile}))
+	assert.Equal(t, 1, exitCode)
+}
@@ -1,90 +1,123 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 
-	"github.com/sirupsen/logrus"
+	"emperror.dev/errors"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	"github.com/google/go-cmp/cmp"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
-	"k8s.io/utils/ptr"
 	"sigs.k8s.io/yaml"
 )
 
+// CleanCrd permit to clean the CRD files that contain '@clean' on description
+// With dry-run, it print the diff instead of writing files. With check, it exit with code 1 if files are not already clean
 func CleanCrd(c *cli.Context) error {
 
 	fileMatches, err := filepath.Glob(c.String("crd-file"))
 	if err != nil {
-		log.Fatal(err)
+		return errors.Wrapf(err, "Error when search files from %s", c.String("crd-file"))
 	}
 
+	notCleanFiles := make([]string, 0)
 	for _, file := range fileMatches {
 
-		logrus.Infof("Start to process file %s", file)
+		log.Infof("Start to process file %s", file)
 
 		// Read current CRD file
-		f, err := os.ReadFile(file)
+		currentCrd, err := loadCRD(file)
 		if err != nil {
-			panic(err)
-		}
-		currentCrd := new(apiv1.CustomResourceDefinition)
-		if err = yaml.Unmarshal(f, currentCrd); err != nil {
-			panic(err)
+			return err
 		}
 
 		// Search special tag on description to clean properties
-		for i, version := range currentCrd.Spec.Versions {
-			for key, item := range version.Schema.OpenAPIV3Schema.Properties {
-				currentCrd.Spec.Versions[i].Schema.OpenAPIV3Schema.Properties[key] = recursiveCleanCrd(item)
-			}
+		cleanedCrd := currentCrd.DeepCopy()
+		CleanCRD(cleanedCrd)
+		if reflect.DeepEqual(currentCrd, cleanedCrd) {
+			log.Infof("File %s is already clean", file)
+			continue
+		}
+
+		if c.Bool("check") {
+			log.Errorf("File %s is not clean", file)
+			notCleanFiles = append(notCleanFiles, file)
+			continue
 		}
 
 		// Write clean CRD
-		b, err := yaml.Marshal(currentCrd)
+		b, err := yaml.Marshal(cleanedCrd)
 		if err != nil {
-			panic(err)
+			return errors.Wrapf(err, "Error when encode CRD from file %s", file)
+		}
+
+		if c.Bool("dry-run") {
+			current, err := yaml.Marshal(currentCrd)
+			if err != nil {
+				return errors.Wrapf(err, "Error when encode CRD from file %s", file)
+			}
+			fmt.Fprintf(c.App.Writer, "Diff of file %s:\n%s\n", file, cmp.Diff(string(current), string(b)))
+			continue
 		}
+
 		if err = os.WriteFile(file, b, 0644); err != nil {
-			panic(err)
+			return errors.Wrapf(err, "Error when write file %s", file)
 		}
 
-		logrus.Infof("Successfully processed file %s", file)
+		log.Infof("Successfully processed file %s", file)
+
+	}
 
+	if len(notCleanFiles) > 0 {
+		return cli.Exit(fmt.Sprintf("Files are not clean: %s", strings.Join(notCleanFiles, ", ")), 1)
 	}
 
 	return nil
 
 }
 
-func recursiveCleanCrd(item apiv1.JSONSchemaProps) apiv1.JSONSchemaProps {
-	if strings.Contains(item.Description, "@clean") {
-		item.Description = strings.Replace(item.Description, "@clean", "", -1)
-		item.Properties = nil
-		item.XPreserveUnknownFields = ptr.To[bool](true)
-
-		if item.Type == "array" {
-			item.Items.Schema.Properties = nil
-		} else {
-			item.Properties = nil
+// CleanCRD permit to replace by 'x-kubernetes-preserve-unknown-fields' the schemas that contain '@clean' on description
+// It walk on all schemas: properties, items, additional properties and combinators
+// The tag need to be a separate word, like on process-crd, so '@cleanup' is not a clean tag
+func CleanCRD(crd *apiv1.CustomResourceDefinition) {
+	helper.WalkCRD(crd, func(version string, path string, props *apiv1.JSONSchemaProps) bool {
+		description, isClean := removeCleanTag(props.Description)
+		if !isClean {
+			return true
 		}
 
-		return item
-	} else {
-		switch item.Type {
-		case "array":
-			for key, val := range item.Items.Schema.Properties {
-				item.Items.Schema.Properties[key] = recursiveCleanCrd(val)
-			}
-		case "object":
-			for key, val := range item.Properties {
-				item.Properties[key] = recursiveCleanCrd(val)
-			}
-		default:
-			return item
+		log.Debugf("Clean field '%s' of version %s", path, version)
+		props.Description = description
+		cleanSchema(props)
+
+		return false
+	})
+}
+
+// removeCleanTag permit to remove the clean tag from description
+// It return false if the description not contain the clean tag
+func removeCleanTag(description string) (cleanDescription string, isClean bool) {
+	lines := strings.Split(description, "\n")
+	keepLines := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if !containsWord(line, CleanTag) {
+			keepLines = append(keepLines, line)
+			continue
+		}
+		isClean = true
+		if line = removeWord(line, CleanTag); line != "" {
+			keepLines = append(keepLines, line)
 		}
 	}
 
-	return item
+	if !isClean {
+		return description, false
+	}
+
+	return strings.TrimSpace(strings.Join(keepLines, "\n")), true
 }
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestCleanCRD(t *testing.T) {
+	crd, err := loadCRD("testdata/cache.example.com_memcacheds.yaml")
+	require.NoError(t, err)
+	expectedCrd, err := loadCRD("testdata/cache.example.com_memcacheds.clean.yaml")
+	require.NoError(t, err)
+
+	CleanCRD(crd)
+	assert.Equal(t, expectedCrd, crd)
+
+	// It is idempotent
+	CleanCRD(crd)
+	assert.Equal(t, expectedCrd, crd)
+
+	// When CRD has no tag
+	crd, err = loadCRD("../../testdata/elasticsearch-operator/config/crd/bases/elasticsearchapi.example.com_roles.yaml")
+	require.NoError(t, err)
+	expectedCrd = crd.DeepCopy()
+	CleanCRD(crd)
+	assert.Equal(t, expectedCrd, crd)
+
+	// When array without items
+	crd = &apiv1.CustomResourceDefinition{
+		Spec: apiv1.CustomResourceDefinitionSpec{
+			Versions: []apiv1.CustomResourceDefinitionVersion{
+				{
+					Name: "v1",
+					Schema: &apiv1.CustomResourceValidation{
+						OpenAPIV3Schema: &apiv1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]apiv1.JSONSchemaProps{
+								"items": {Type: "array", Description: "@clean"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.NotPanics(t, func() { CleanCRD(crd) })
+	assert.True(t, *crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["items"].XPreserveUnknownFields)
+
+	// When tag is part of another word, like on process-crd
+	crd = &apiv1.CustomResourceDefinition{
+		Spec: apiv1.CustomResourceDefinitionSpec{
+			Versions: []apiv1.CustomResourceDefinitionVersion{
+				{
+					Name: "v1",
+					Schema: &apiv1.CustomResourceValidation{
+						OpenAPIV3Schema: &apiv1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]apiv1.JSONSchemaProps{
+								"cleanup": {
+									Type:        "object",
+									Description: "Run @cleanup hooks",
+									Properties:  map[string]apiv1.JSONSchemaProps{"foo": {Type: "string"}},
+								},
+								"config": {
+									Type:        "object",
+									Description: "The config @clean\nof application",
+									Properties:  map[string]apiv1.JSONSchemaProps{"foo": {Type: "string"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	CleanCRD(crd)
+	cleanup := crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["cleanup"]
+	assert.Equal(t, "Run @cleanup hooks", cleanup.Description)
+	assert.Nil(t, cleanup.XPreserveUnknownFields)
+	assert.Contains(t, cleanup.Properties, "foo")
+	config := crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["config"]
+	assert.Equal(t, "The config\nof application", config.Description)
+	assert.True(t, *config.XPreserveUnknownFields)
+	assert.Empty(t, config.Properties)
+}
+
+func TestCleanCrd(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "crd.yaml")
+	original, err := os.ReadFile("testdata/cache.example.com_memcacheds.yaml")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(file, original, 0644))
+
+	exitCode := 0
+	cli.OsExiter = func(code int) { exitCode = code }
+	defer func() { cli.OsExiter = os.Exit }()
+
+	// When check and file is not clean
+	assert.Error(t, run([]string{"crd", "--no-color", "clean-crd", "--crd-file", file, "--check"}))
+	assert.Equal(t, 1, exitCode)
+	exitCode = 0
+
+	// When dry run, it print the diff without change the file
+	app := cli.NewApp()
+	buf := new(bytes.Buffer)
+	app.Writer = buf
+	app.Flags = []cli.Flag{&cli.StringFlag{Name: "crd-file"}, &cli.BoolFlag{Name: "dry-run"}}
+	app.Action = CleanCrd
+	assert.NoError(t, app.Run([]string{"crd", "--crd-file", file, "--dry-run"}))
+	assert.Contains(t, buf.String(), "x-kubernetes-preserve-unknown-fields")
+	current, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, original, current)
+
+	// When clean
+	assert.NoError(t, run([]string{"crd", "--no-color", "clean-crd", "--crd-file", file}))
+	crd, err := loadCRD(file)
+	require.NoError(t, err)
+	expectedCrd, err := loadCRD("testdata/cache.example.com_memcacheds.clean.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, expectedCrd, crd)
+
+	// When check and file is clean
+	assert.NoError(t, run([]string{"crd", "--no-color", "clean-crd", "--crd-file", file, "--check"}))
+	assert.Equal(t, 0, exitCode)
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"emperror.dev/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// ObjectMarker is the marker to set on CRD type to generate the methods of RemoteObject or MultiPhaseObject
+	ObjectMarker = "+operator-sdk-extra:object="
+
+	// ExternalNameMarker is the marker to set on remote CRD type to use a field as external name
+	// When the field is empty, the resource name is used
+	ExternalNameMarker = "+operator-sdk-extra:externalName="
+
+	// DefaultGeneratedFile is the default file name where the methods are generated
+	DefaultGeneratedFile = "zz_generated.operator-sdk-extra.go"
+
+	RemoteObjectKind     = "remote"
+	MultiPhaseObjectKind = "multiphase"
+)
+
+// generatedObject is the CRD type on which generate the methods
+type generatedObject struct {
+	Name                 string
+	Kind                 string
+	ExternalNameField    string
+	GenerateGetStatus    bool
+	GenerateExternalName bool
+	StatusInterface      string
+	ObjectInterface      string
+}
+
+var generatedTemplate = template.Must(template.New("generated").Parse(`{{ .Header }}
+// Code generated by operator-sdk-extra. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"github.com/disaster37/operator-sdk-extra/pkg/object"
+)
+{{ range .Objects }}
+var _ object.{{ .ObjectInterface }} = &{{ .Name }}{}
+{{ if .GenerateGetStatus }}
+// GetStatus return the status object
+func (o *{{ .Name }}) GetStatus() object.{{ .StatusInterface }} {
+	return &o.Status
+}
+{{ end }}{{ if .GenerateExternalName }}{{ if .ExternalNameField }}
+// GetExternalName return the external name
+// If {{ .ExternalNameField }} is empty, it use the resource name
+func (o *{{ .Name }}) GetExternalName() string {
+	if o.{{ .ExternalNameField }} == "" {
+		return o.Name
+	}
+
+	return o.{{ .ExternalNameField }}
+}
+{{ else }}
+// GetExternalName return the external name
+// It use the resource name
+func (o *{{ .Name }}) GetExternalName() string {
+	return o.Name
+}
+{{ end }}{{ end }}{{ end }}`))
+
+// GenerateObjects permit to generate the methods of RemoteObject and MultiPhaseObject on CRD types that have the marker '+operator-sdk-extra:object'
+func GenerateObjects(c *cli.Context) error {
+	var header string
+	if c.String("header-file") != "" {
+		b, err := os.ReadFile(c.String("header-file"))
+		if err != nil {
+			return errors.Wrapf(err, "Error when read header file %s", c.String("header-file"))
+		}
+		header = string(b)
+	}
+
+	dirs, err := filepath.Glob(c.String("path"))
+	if err != nil {
+		return errors.Wrapf(err, "Error when search directories from %s", c.String("path"))
+	}
+
+	for _, dir := range dirs {
+		log.Infof("Start to process directory %s", dir)
+
+		content, err := generateObjects(dir, c.String("output-file"), header)
+		if err != nil {
+			return errors.Wrapf(err, "Error when generate objects on directory %s", dir)
+		}
+		if content == nil {
+			log.Infof("No object marker found on directory %s", dir)
+			continue
+		}
+
+		if err = os.WriteFile(filepath.Join(dir, c.String("output-file")), content, 0644); err != nil {
+			return errors.Wrapf(err, "Error when write generated file on directory %s", dir)
+		}
+
+		log.Infof("Successfully processed directory %s", dir)
+	}
+
+	return nil
+}
+
+// generateObjects permit to compute the generated file of the Go package on dir
+// It return nil content if there are no object marker
+func generateObjects(dir string, outputFile string, header string) (content []byte, err error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	packageName := ""
+	objects := map[string]*generatedObject{}
+	// Methods already declared by receiver type
+	methods := map[string]map[string]bool{}
+
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") || filepath.Base(file) == outputFile {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error when parse file %s", file)
+		}
+		packageName = f.Name.Name
+
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if receiver := getReceiverTypeName(d); receiver != "" {
+					if methods[receiver] == nil {
+						methods[receiver] = map[string]bool{}
+					}
+					methods[receiver][d.Name.Name] = true
+				}
+			case *ast.GenDecl:
+				if d.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range d.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					markers := getMarkers(d, typeSpec)
+					o, err := newGeneratedObject(typeSpec.Name.Name, markers)
+					if err != nil {
+						return nil, errors.Wrapf(err, "Error on type %s", typeSpec.Name.Name)
+					}
+					if o != nil {
+						objects[o.Name] = o
+					}
+				}
+			}
+		}
+	}
+
+	if len(objects) == 0 {
+		return nil, nil
+	}
+
+	// Skip the methods already declared
+	orderedObjects := make([]*generatedObject, 0, len(objects))
+	for _, o := range objects {
+		o.GenerateGetStatus = !methods[o.Name]["GetStatus"]
+		o.GenerateExternalName = o.Kind == RemoteObjectKind && !methods[o.Name]["GetExternalName"]
+		orderedObjects = append(orderedObjects, o)
+	}
+	sort.Slice(orderedObjects, func(i, j int) bool {
+		return orderedObjects[i].Name < orderedObjects[j].Name
+	})
+
+	buf := new(bytes.Buffer)
+	if err = generatedTemplate.Execute(buf, map[string]any{
+		"Header":  header,
+		"Package": packageName,
+		"Objects": orderedObjects,
+	}); err != nil {
+		return nil, errors.Wrap(err, "Error when execute template")
+	}
+
+	content, err = format.Source(buf.Bytes())
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error when format generated file:\n%s", buf.String())
+	}
+
+	return content, nil
+}
+
+// newGeneratedObject permit to get the object to generate from markers
+// It return nil if there are no object marker
+func newGeneratedObject(name string, markers []string) (o *generatedObject, err error) {
+	for _, marker := range markers {
+		switch {
+		case strings.HasPrefix(marker, ObjectMarker):
+			if o == nil {
+				o = &generatedObject{Name: name}
+			}
+			o.Kind = strings.TrimPrefix(marker, ObjectMarker)
+			switch o.Kind {
+			case RemoteObjectKind:
+				o.ObjectInterface = "RemoteObject"
+				o.StatusInterface = "RemoteObjectStatus"
+			case MultiPhaseObjectKind:
+				o.ObjectInterface = "MultiPhaseObject"
+				o.StatusInterface = "MultiPhaseObjectStatus"
+			default:
+				return nil, errors.Errorf("Object kind '%s' not supported, it must be '%s' or '%s'", o.Kind, RemoteObjectKind, MultiPhaseObjectKind)
+			}
+		case strings.HasPrefix(marker, ExternalNameMarker):
+			if o == nil {
+				o = &generatedObject{Name: name}
+			}
+			o.ExternalNameField = strings.TrimPrefix(marker, ExternalNameMarker)
+		}
+	}
+
+	if o != nil && o.Kind == "" {
+		return nil, errors.Errorf("Marker '%s' is required when use '%s'", ObjectMarker, ExternalNameMarker)
+	}
+	if o != nil && o.ExternalNameField != "" && o.Kind != RemoteObjectKind {
+		return nil, errors.Errorf("Marker '%s' is only supported on '%s' object", ExternalNameMarker, RemoteObjectKind)
+	}
+
+	return o, nil
+}
+
+// getMarkers permit to get the markers of type
+// It only read the doc of type, or the doc of declaration when it contain only this type. The markers need to be on the doc, without empty line before the type
+func getMarkers(d *ast.GenDecl, typeSpec *ast.TypeSpec) (markers []string) {
+	doc := typeSpec.Doc
+	if doc == nil && len(d.Specs) == 1 {
+		doc = d.Doc
+	}
+	if doc == nil {
+		return nil
+	}
+
+	for _, comment := range doc.List {
+		text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+		if strings.HasPrefix(text, "+") {
+			markers = append(markers, text)
+		}
+	}
+
+	return markers
+}
+
+// getReceiverTypeName permit to get the type name of method receiver
+// It return empty string for function
+func getReceiverTypeName(d *ast.FuncDecl) string {
+	if d.Recv == nil || len(d.Recv.List) == 0 {
+		return ""
+	}
+
+	expr := d.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+
+	return ""
+}
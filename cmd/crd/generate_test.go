@@ -0,0 +1,133 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testGenerateTypes = `package v1alpha1
+
+import (
+	"github.com/disaster37/operator-sdk-extra/pkg/apis"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type RoleSpec struct {
+	Name string ` + "`json:\"name,omitempty\"`" + `
+}
+
+type RoleStatus struct {
+	apis.BasicRemoteObjectStatus ` + "`json:\",inline\"`" + `
+}
+
+// Role is the Schema for the roles API
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+// +operator-sdk-extra:object=remote
+// +operator-sdk-extra:externalName=Spec.Name
+type Role struct {
+	metav1.TypeMeta   ` + "`json:\",inline\"`" + `
+	metav1.ObjectMeta ` + "`json:\"metadata,omitempty\"`" + `
+
+	Spec   RoleSpec   ` + "`json:\"spec,omitempty\"`" + `
+	Status RoleStatus ` + "`json:\"status,omitempty\"`" + `
+}
+
+type MemcachedStatus struct {
+	apis.BasicMultiPhaseObjectStatus ` + "`json:\",inline\"`" + `
+}
+
+// Memcached is the Schema for the memcacheds API
+// +operator-sdk-extra:object=multiphase
+type Memcached struct {
+	metav1.TypeMeta   ` + "`json:\",inline\"`" + `
+	metav1.ObjectMeta ` + "`json:\"metadata,omitempty\"`" + `
+
+	Status MemcachedStatus ` + "`json:\"status,omitempty\"`" + `
+}
+
+// User already implement GetStatus
+// +operator-sdk-extra:object=remote
+type User struct {
+	metav1.TypeMeta   ` + "`json:\",inline\"`" + `
+	metav1.ObjectMeta ` + "`json:\"metadata,omitempty\"`" + `
+
+	Status RoleStatus ` + "`json:\"status,omitempty\"`" + `
+}
+
+// +operator-sdk-extra:object=remote
+
+// RoleList is not generated, because of the marker is not on its doc
+type RoleList struct {
+	metav1.TypeMeta ` + "`json:\",inline\"`" + `
+	metav1.ListMeta ` + "`json:\"metadata,omitempty\"`" + `
+	Items           []Role ` + "`json:\"items\"`" + `
+}
+`
+
+const testGenerateFuncs = `package v1alpha1
+
+import "github.com/disaster37/operator-sdk-extra/pkg/object"
+
+func (o *User) GetStatus() object.RemoteObjectStatus {
+	return &o.Status
+}
+`
+
+func TestGenerateObjects(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "types.go"), []byte(testGenerateTypes), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "funcs.go"), []byte(testGenerateFuncs), 0644))
+
+	content, err := generateObjects(dir, DefaultGeneratedFile, "// Header\n")
+	require.NoError(t, err)
+
+	// The generated file is valid Go
+	f, err := parser.ParseFile(token.NewFileSet(), DefaultGeneratedFile, content, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "v1alpha1", f.Name.Name)
+
+	expected, err := os.ReadFile("testdata/zz_generated.operator-sdk-extra.go.golden")
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(content))
+
+	// When generated file already exist, it is ignored
+	require.NoError(t, os.WriteFile(filepath.Join(dir, DefaultGeneratedFile), content, 0644))
+	newContent, err := generateObjects(dir, DefaultGeneratedFile, "// Header\n")
+	require.NoError(t, err)
+	assert.Equal(t, content, newContent)
+}
+
+func TestGenerateObjectsWithoutMarker(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "funcs.go"), []byte(testGenerateFuncs), 0644))
+
+	content, err := generateObjects(dir, DefaultGeneratedFile, "")
+	assert.NoError(t, err)
+	assert.Nil(t, content)
+}
+
+func TestNewGeneratedObject(t *testing.T) {
+	// When bad object kind
+	_, err := newGeneratedObject("Test", []string{"+operator-sdk-extra:object=bad"})
+	assert.Error(t, err)
+
+	// When external name without object marker
+	_, err = newGeneratedObject("Test", []string{"+operator-sdk-extra:externalName=Spec.Name"})
+	assert.Error(t, err)
+
+	// When external name on multiphase object
+	_, err = newGeneratedObject("Test", []string{"+operator-sdk-extra:object=multiphase", "+operator-sdk-extra:externalName=Spec.Name"})
+	assert.Error(t, err)
+
+	// When no marker
+	o, err := newGeneratedObject("Test", []string{"+kubebuilder:object:root=true"})
+	assert.NoError(t, err)
+	assert.Nil(t, o)
+}
@@ -39,11 +39,133 @@ func run(args []string) error {
 			Flags: []cli.Flag{
 				&cli.StringFlag{
 					Name:  "crd-file",
-					Usage: "The CRD files to clean. Toy can use glob path",
+					Usage: "The CRD files to clean. You can use glob path",
+				},
+				&cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "Print the diff instead of writing files",
+				},
+				&cli.BoolFlag{
+					Name:  "check",
+					Usage: "Exit with code 1 if files are not already clean",
 				},
 			},
 			Action: CleanCrd,
 		},
+		{
+			Name:  "process-crd",
+			Usage: "post-process crd from description tags '@clean', '@immutable', '@cel' and from config file",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "crd-file",
+					Usage: "The CRD files to process. You can use glob path",
+				},
+				&cli.StringFlag{
+					Name:  "config",
+					Usage: "The config file to trim descriptions, drop versions and add printer columns",
+				},
+			},
+			Action: ProcessCrd,
+		},
+		{
+			Name:  "analyze-crd",
+			Usage: "report the size of crd, the heavy properties and the candidates to '@clean'",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "crd-file",
+					Usage: "The CRD files to analyze. You can use glob path",
+				},
+				&cli.IntFlag{
+					Name:  "top",
+					Usage: "The number of heavy properties to display",
+					Value: 10,
+				},
+				&cli.IntFlag{
+					Name:  "clean-threshold",
+					Usage: "The size in bytes from which a property is a candidate to '@clean'",
+					Value: 20480,
+				},
+			},
+			Action: AnalyzeCrd,
+		},
+		{
+			Name:  "compare-crd",
+			Usage: "compare two crd revisions and exit with code 1 on breaking changes",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "old-crd-file",
+					Usage:    "The CRD file of previous revision",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:     "new-crd-file",
+					Usage:    "The CRD file of new revision",
+					Required: true,
+				},
+			},
+			Action: CompareCrd,
+		},
+		{
+			Name:  "generate",
+			Usage: "generate the methods of RemoteObject and MultiPhaseObject on CRD types that have marker '+operator-sdk-extra:object'",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "path",
+					Usage: "The directory of API package. You can use glob path",
+					Value: ".",
+				},
+				&cli.StringFlag{
+					Name:  "output-file",
+					Usage: "The file name to generate on API package",
+					Value: DefaultGeneratedFile,
+				},
+				&cli.StringFlag{
+					Name:  "header-file",
+					Usage: "The header file to put on top of generated file, like hack/boilerplate.go.txt",
+				},
+			},
+			Action: GenerateObjects,
+		},
+		{
+			Name:  "scaffold",
+			Usage: "scaffold the controller, actions, external handler and envtest suite of remote or multiphase kind",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "kind",
+					Usage:    "The kind of CRD, like Role",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:     "type",
+					Usage:    "The reconciler type: remote or multiphase",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:     "group",
+					Usage:    "The API group of CRD, like elasticsearchapi.k8s.webcenter.fr",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:     "api-import",
+					Usage:    "The import path of API package, like github.com/disaster37/elasticsearch-operator/api/v1",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:  "output-dir",
+					Usage: "The directory where generate the controller, from the project root",
+					Value: "controllers",
+				},
+				&cli.StringFlag{
+					Name:  "header-file",
+					Usage: "The header file to put on top of generated files, like hack/boilerplate.go.txt",
+				},
+				&cli.BoolFlag{
+					Name:  "force",
+					Usage: "Override the files that already exist",
+				},
+			},
+			Action: Scaffold,
+		},
 	}
 
 	app.Before = func(c *cli.Context) error {
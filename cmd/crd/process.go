@@ -0,0 +1,348 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// CleanTag is the description tag to replace the schema by 'x-kubernetes-preserve-unknown-fields'
+	CleanTag = "@clean"
+
+	// ImmutableTag is the description tag to add CEL rule that forbid to change the field
+	ImmutableTag = "@immutable"
+
+	// CELTag is the description tag to add CEL rule on field, like '@cel self.size() <= 10 @message Max 10 items'
+	CELTag = "@cel"
+
+	// CELMessageTag is the separator of the message on CELTag
+	CELMessageTag = "@message"
+
+	// ImmutableRule is the CEL rule to forbid to change field
+	ImmutableRule = helper.ImmutableRule
+
+	// ImmutableMessage is the message when ImmutableRule not match
+	ImmutableMessage = helper.ImmutableMessage
+)
+
+// ProcessConfig is the configuration of CRD post-processor
+type ProcessConfig struct {
+	// MaxDescriptionLength is the max length of descriptions. Longer descriptions are trimmed
+	// No trim when 0
+	MaxDescriptionLength int `json:"maxDescriptionLength,omitempty"`
+
+	// DropVersions is the versions to remove from CRD
+	DropVersions []ProcessVersionSelector `json:"dropVersions,omitempty"`
+
+	// PrinterColumns is the printer columns to add on CRD
+	PrinterColumns []ProcessPrinterColumns `json:"printerColumns,omitempty"`
+}
+
+// ProcessVersionSelector permit to select versions of CRD
+type ProcessVersionSelector struct {
+	// CRD is the CRD name, like roles.elasticsearchapi.k8s.webcenter.fr
+	// All CRD when empty
+	CRD string `json:"crd,omitempty"`
+
+	// Versions is the version names
+	// All versions when empty
+	Versions []string `json:"versions,omitempty"`
+}
+
+// ProcessPrinterColumns is the printer columns to add on selected versions
+type ProcessPrinterColumns struct {
+	ProcessVersionSelector `json:",inline"`
+
+	// Columns is the printer columns. A column with the same name is replaced
+	Columns []apiv1.CustomResourceColumnDefinition `json:"columns"`
+}
+
+// Match permit to check if the version of CRD is selected
+func (h ProcessVersionSelector) Match(crdName string, version string) bool {
+	if h.CRD != "" && h.CRD != crdName {
+		return false
+	}
+	if len(h.Versions) == 0 {
+		return true
+	}
+	for _, v := range h.Versions {
+		if v == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LoadProcessConfig permit to read the config file of CRD post-processor
+// It return empty config when file is empty
+func LoadProcessConfig(file string) (config *ProcessConfig, err error) {
+	config = &ProcessConfig{}
+	if file == "" {
+		return config, nil
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error when read config file %s", file)
+	}
+	if err = yaml.UnmarshalStrict(b, config); err != nil {
+		return nil, errors.Wrapf(err, "Error when decode config file %s", file)
+	}
+
+	return config, nil
+}
+
+// ProcessCrd permit to post-process CRD files from description tags and config file
+func ProcessCrd(c *cli.Context) error {
+	config, err := LoadProcessConfig(c.String("config"))
+	if err != nil {
+		return err
+	}
+
+	fileMatches, err := filepath.Glob(c.String("crd-file"))
+	if err != nil {
+		return errors.Wrapf(err, "Error when search files from %s", c.String("crd-file"))
+	}
+
+	for _, file := range fileMatches {
+		log.Infof("Start to process file %s", file)
+
+		isChanged, err := processCrdFile(file, config)
+		if err != nil {
+			return err
+		}
+		if isChanged {
+			log.Infof("Successfully processed file %s", file)
+		} else {
+			log.Infof("File %s is already processed", file)
+		}
+	}
+
+	return nil
+}
+
+// processCrdFile permit to process one CRD file and to write it only if it changed
+func processCrdFile(file string, config *ProcessConfig) (isChanged bool, err error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return false, errors.Wrapf(err, "Error when read file %s", file)
+	}
+	crd := &apiv1.CustomResourceDefinition{}
+	if err = yaml.Unmarshal(b, crd); err != nil {
+		return false, errors.Wrapf(err, "Error when decode CRD from file %s", file)
+	}
+
+	if err = ProcessCRD(crd, config); err != nil {
+		return false, errors.Wrapf(err, "Error when process CRD from file %s", file)
+	}
+
+	processed, err := yaml.Marshal(crd)
+	if err != nil {
+		return false, errors.Wrapf(err, "Error when encode CRD from file %s", file)
+	}
+	if bytes.Equal(b, processed) {
+		return false, nil
+	}
+
+	if err = os.WriteFile(file, processed, 0644); err != nil {
+		return false, errors.Wrapf(err, "Error when write file %s", file)
+	}
+
+	return true, nil
+}
+
+// ProcessCRD permit to apply the description tags and the config on CRD
+// It is idempotent: the tags are removed from descriptions once applied, and the config is not applied twice
+func ProcessCRD(crd *apiv1.CustomResourceDefinition, config *ProcessConfig) (err error) {
+	if config == nil {
+		config = &ProcessConfig{}
+	}
+
+	// Drop versions
+	versions := make([]apiv1.CustomResourceDefinitionVersion, 0, len(crd.Spec.Versions))
+	for _, version := range crd.Spec.Versions {
+		isDropped := false
+		for _, selector := range config.DropVersions {
+			if selector.Match(crd.Name, version.Name) {
+				isDropped = true
+				break
+			}
+		}
+		if !isDropped {
+			versions = append(versions, version)
+			continue
+		}
+		if version.Storage {
+			return errors.Errorf("Version %s can't be dropped because of it's the storage version", version.Name)
+		}
+		log.Debugf("Drop version %s", version.Name)
+	}
+	crd.Spec.Versions = versions
+
+	// Add printer columns
+	for i, version := range crd.Spec.Versions {
+		for _, printerColumns := range config.PrinterColumns {
+			if printerColumns.Match(crd.Name, version.Name) {
+				crd.Spec.Versions[i].AdditionalPrinterColumns = mergePrinterColumns(crd.Spec.Versions[i].AdditionalPrinterColumns, printerColumns.Columns)
+			}
+		}
+	}
+
+	// Apply description tags
+	helper.WalkCRD(crd, func(version string, path string, props *apiv1.JSONSchemaProps) bool {
+		if err != nil {
+			return false
+		}
+
+		walkChildren := true
+		description, tags := extractTags(props.Description)
+		for _, tag := range tags {
+			switch {
+			case tag == CleanTag:
+				cleanSchema(props)
+				walkChildren = false
+			case tag == ImmutableTag:
+				addValidationRule(props, apiv1.ValidationRule{Rule: ImmutableRule, Message: ImmutableMessage})
+			case strings.HasPrefix(tag, CELTag+" "):
+				rule, message, _ := strings.Cut(strings.TrimSpace(strings.TrimPrefix(tag, CELTag)), CELMessageTag)
+				if strings.TrimSpace(rule) == "" {
+					err = errors.Errorf("CEL rule can't be empty on field '%s' of version %s", path, version)
+					return false
+				}
+				addValidationRule(props, apiv1.ValidationRule{Rule: strings.TrimSpace(rule), Message: strings.TrimSpace(message)})
+			}
+		}
+		props.Description = trimDescription(description, config.MaxDescriptionLength)
+
+		return walkChildren
+	})
+
+	return err
+}
+
+// extractTags permit to get the tags of description, and the description without them
+// A tag start with '@' and finish at the end of line
+func extractTags(description string) (cleanDescription string, tags []string) {
+	lines := strings.Split(description, "\n")
+	keepLines := make([]string, 0, len(lines))
+	for _, line := range lines {
+		trimLine := strings.TrimSpace(line)
+		switch {
+		case trimLine == CleanTag, trimLine == ImmutableTag, strings.HasPrefix(trimLine, CELTag+" "):
+			tags = append(tags, trimLine)
+		case containsWord(line, CleanTag):
+			// Keep compatibility with '@clean' set inside the description, like clean-crd
+			tags = append(tags, CleanTag)
+			keepLines = append(keepLines, removeWord(line, CleanTag))
+		default:
+			keepLines = append(keepLines, line)
+		}
+	}
+
+	if len(tags) == 0 {
+		return description, nil
+	}
+
+	return strings.TrimSpace(strings.Join(keepLines, "\n")), tags
+}
+
+// containsWord permit to know if the line contain the word, separated by spaces
+func containsWord(line string, word string) bool {
+	for _, field := range strings.Fields(line) {
+		if field == word {
+			return true
+		}
+	}
+
+	return false
+}
+
+// removeWord permit to remove the word, separated by spaces, from the line
+func removeWord(line string, word string) string {
+	fields := strings.Fields(line)
+	keepFields := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field != word {
+			keepFields = append(keepFields, field)
+		}
+	}
+
+	return strings.Join(keepFields, " ")
+}
+
+// cleanSchema permit to replace the schema by 'x-kubernetes-preserve-unknown-fields'
+// The sub schemas are removed, because of they can't reference the removed properties
+func cleanSchema(props *apiv1.JSONSchemaProps) {
+	props.XPreserveUnknownFields = ptr.To[bool](true)
+	props.Properties = nil
+	props.Required = nil
+	props.AdditionalProperties = nil
+	props.AllOf = nil
+	props.AnyOf = nil
+	props.OneOf = nil
+	props.Not = nil
+
+	// The items of arrays, and arrays of arrays, need also to preserve unknown fields
+	if props.Items != nil {
+		if props.Items.Schema != nil {
+			cleanSchema(props.Items.Schema)
+		}
+		for i := range props.Items.JSONSchemas {
+			cleanSchema(&props.Items.JSONSchemas[i])
+		}
+	}
+}
+
+// addValidationRule permit to add the CEL rule if not already exist
+func addValidationRule(props *apiv1.JSONSchemaProps, rule apiv1.ValidationRule) {
+	for _, validation := range props.XValidations {
+		if validation.Rule == rule.Rule {
+			return
+		}
+	}
+	props.XValidations = append(props.XValidations, rule)
+}
+
+// mergePrinterColumns permit to add the columns. The columns with the same name are replaced
+func mergePrinterColumns(currentColumns []apiv1.CustomResourceColumnDefinition, columns []apiv1.CustomResourceColumnDefinition) []apiv1.CustomResourceColumnDefinition {
+	for _, column := range columns {
+		isFound := false
+		for i, currentColumn := range currentColumns {
+			if currentColumn.Name == column.Name {
+				currentColumns[i] = column
+				isFound = true
+				break
+			}
+		}
+		if !isFound {
+			currentColumns = append(currentColumns, column)
+		}
+	}
+
+	return currentColumns
+}
+
+// trimDescription permit to trim description to max length, '...' included
+// No trim when max length is 0
+func trimDescription(description string, maxLength int) string {
+	runes := []rune(description)
+	if maxLength <= 0 || len(runes) <= maxLength {
+		return description
+	}
+	if maxLength <= 3 {
+		return string(runes[:maxLength])
+	}
+
+	return strings.TrimSpace(string(runes[:maxLength-3])) + "..."
+}
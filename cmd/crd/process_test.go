@@ -0,0 +1,171 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const testProcessCrd = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: roles.test.example.com
+spec:
+  group: test.example.com
+  names:
+    kind: Role
+    plural: roles
+  scope: Namespaced
+  versions:
+  - name: v1alpha1
+    served: true
+    storage: false
+    schema:
+      openAPIV3Schema:
+        type: object
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        description: Role is the Schema for the roles API
+        type: object
+        properties:
+          spec:
+            type: object
+            properties:
+              name:
+                description: |-
+                  Name is the role name
+                  @immutable
+                type: string
+              replicas:
+                description: |-
+                  Replicas is the number of replicas
+                  @cel self <= 10 @message Max 10 replicas
+                type: integer
+              settings:
+                description: Settings is free settings @clean
+                type: object
+                properties:
+                  foo:
+                    type: string
+              users:
+                description: Users is the list of users with a very long description
+                type: array
+                items:
+                  type: object
+                  properties:
+                    name:
+                      description: |-
+                        Name of user
+                        @immutable
+                      type: string
+`
+
+func TestProcessCRD(t *testing.T) {
+	crd := &apiv1.CustomResourceDefinition{}
+	require.NoError(t, yaml.Unmarshal([]byte(testProcessCrd), crd))
+	config := &ProcessConfig{
+		MaxDescriptionLength: 30,
+		DropVersions: []ProcessVersionSelector{
+			{CRD: "roles.test.example.com", Versions: []string{"v1alpha1"}},
+		},
+		PrinterColumns: []ProcessPrinterColumns{
+			{
+				Columns: []apiv1.CustomResourceColumnDefinition{
+					{Name: "Sync", Type: "boolean", JSONPath: ".status.isSync"},
+				},
+			},
+		},
+	}
+
+	require.NoError(t, ProcessCRD(crd, config))
+
+	require.Len(t, crd.Spec.Versions, 1)
+	assert.Equal(t, "v1", crd.Spec.Versions[0].Name)
+	assert.Equal(t, []apiv1.CustomResourceColumnDefinition{{Name: "Sync", Type: "boolean", JSONPath: ".status.isSync"}}, crd.Spec.Versions[0].AdditionalPrinterColumns)
+
+	spec := crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"]
+	assert.Equal(t, "Name is the role name", spec.Properties["name"].Description)
+	assert.Equal(t, apiv1.ValidationRules{{Rule: ImmutableRule, Message: ImmutableMessage}}, spec.Properties["name"].XValidations)
+	assert.Equal(t, apiv1.ValidationRules{{Rule: "self <= 10", Message: "Max 10 replicas"}}, spec.Properties["replicas"].XValidations)
+	assert.Equal(t, "Settings is free settings", spec.Properties["settings"].Description)
+	assert.Nil(t, spec.Properties["settings"].Properties)
+	assert.True(t, *spec.Properties["settings"].XPreserveUnknownFields)
+	assert.Equal(t, "Users is the list of users...", spec.Properties["users"].Description)
+	assert.Equal(t, apiv1.ValidationRules{{Rule: ImmutableRule, Message: ImmutableMessage}}, spec.Properties["users"].Items.Schema.Properties["name"].XValidations)
+
+	// It is idempotent
+	expected := crd.DeepCopy()
+	require.NoError(t, ProcessCRD(crd, config))
+	assert.Equal(t, expected, crd)
+
+	// When drop storage version
+	crd = &apiv1.CustomResourceDefinition{}
+	require.NoError(t, yaml.Unmarshal([]byte(testProcessCrd), crd))
+	assert.Error(t, ProcessCRD(crd, &ProcessConfig{DropVersions: []ProcessVersionSelector{{Versions: []string{"v1"}}}}))
+}
+
+func TestProcessCrdFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "crd.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(testProcessCrd), 0644))
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("maxDescriptionLength: 50\ndropVersions:\n- versions: [v1alpha1]\n"), 0644))
+
+	config, err := LoadProcessConfig(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, 50, config.MaxDescriptionLength)
+
+	isChanged, err := processCrdFile(file, config)
+	require.NoError(t, err)
+	assert.True(t, isChanged)
+
+	// When process again, the file not change
+	isChanged, err = processCrdFile(file, config)
+	require.NoError(t, err)
+	assert.False(t, isChanged)
+
+	// When config has unknown field
+	require.NoError(t, os.WriteFile(configFile, []byte("unknown: true\n"), 0644))
+	_, err = LoadProcessConfig(configFile)
+	assert.Error(t, err)
+}
+
+func TestWalkSchema(t *testing.T) {
+	crd := &apiv1.CustomResourceDefinition{}
+	require.NoError(t, yaml.Unmarshal([]byte(testProcessCrd), crd))
+
+	paths := []string{}
+	helper.WalkSchema(crd.Spec.Versions[1].Schema.OpenAPIV3Schema, "", func(path string, props *apiv1.JSONSchemaProps) bool {
+		paths = append(paths, path)
+		props.Description = "changed"
+		return path != "spec.settings"
+	})
+	assert.Equal(t, []string{"", "spec", "spec.name", "spec.replicas", "spec.settings", "spec.users", "spec.users[]", "spec.users[].name"}, paths)
+	assert.Equal(t, "changed", crd.Spec.Versions[1].Schema.OpenAPIV3Schema.Properties["spec"].Properties["users"].Items.Schema.Properties["name"].Description)
+}
+
+func TestExtractTags(t *testing.T) {
+	// When tags are on their own lines
+	description, tags := extractTags("Name is the role name\n@immutable\n@cel self != '' @message Name is required")
+	assert.Equal(t, "Name is the role name", description)
+	assert.Equal(t, []string{ImmutableTag, CELTag + " self != '' @message Name is required"}, tags)
+
+	// When clean tag is inside the description
+	description, tags = extractTags("Settings is @clean free settings")
+	assert.Equal(t, "Settings is free settings", description)
+	assert.Equal(t, []string{CleanTag}, tags)
+
+	// When word only start with clean tag
+	description, tags = extractTags("Cleanup policy, see @cleanup")
+	assert.Equal(t, "Cleanup policy, see @cleanup", description)
+	assert.Empty(t, tags)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"go/format"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"emperror.dev/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+//go:embed templates
+var scaffoldTemplates embed.FS
+
+// scaffoldFile is the file to generate from template
+type scaffoldFile struct {
+	template string
+	fileName string
+}
+
+// scaffoldData is the data used by templates
+type scaffoldData struct {
+	Header    string
+	Package   string
+	Kind      string
+	LowerKind string
+	FileName  string
+	Plural    string
+	Group     string
+	ApiImport string
+	CRDPath   string
+}
+
+// Scaffold permit to generate the skeleton of remote or multiphase controller for a kind
+func Scaffold(c *cli.Context) error {
+	var header string
+	if c.String("header-file") != "" {
+		b, err := os.ReadFile(c.String("header-file"))
+		if err != nil {
+			return errors.Wrapf(err, "Error when read header file %s", c.String("header-file"))
+		}
+		header = string(b)
+	}
+
+	files, err := scaffoldFiles(c.String("type"), c.String("kind"), c.String("group"), c.String("api-import"), c.String("output-dir"), header)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(c.String("output-dir"), 0755); err != nil {
+		return errors.Wrapf(err, "Error when create directory %s", c.String("output-dir"))
+	}
+
+	for fileName, content := range files {
+		file := filepath.Join(c.String("output-dir"), fileName)
+		if _, err = os.Stat(file); err == nil && !c.Bool("force") {
+			log.Warnf("File %s already exist, skip it. Use --force to override it", file)
+			continue
+		}
+		if err = os.WriteFile(file, content, 0644); err != nil {
+			return errors.Wrapf(err, "Error when write file %s", file)
+		}
+		log.Infof("Successfully generated file %s", file)
+	}
+
+	return nil
+}
+
+// scaffoldFiles permit to compute the files of controller, mapped by file name
+func scaffoldFiles(reconcilerType, kind, group, apiImport, outputDir, header string) (files map[string][]byte, err error) {
+	if kind == "" || !unicode.IsUpper(rune(kind[0])) {
+		return nil, errors.Errorf("Kind '%s' must start with upper case", kind)
+	}
+	if group == "" {
+		return nil, errors.New("Group can't be empty")
+	}
+	if apiImport == "" {
+		return nil, errors.New("API import path can't be empty")
+	}
+
+	fileName := strings.ToLower(kind)
+	var scaffoldFiles []scaffoldFile
+	switch reconcilerType {
+	case RemoteObjectKind:
+		scaffoldFiles = []scaffoldFile{
+			{template: "templates/remote/controller.go.tmpl", fileName: fileName + "_controller.go"},
+			{template: "templates/remote/reconciler.go.tmpl", fileName: fileName + "_reconciler.go"},
+			{template: "templates/remote/external_reconciler.go.tmpl", fileName: fileName + "_external_reconciler.go"},
+		}
+	case MultiPhaseObjectKind:
+		scaffoldFiles = []scaffoldFile{
+			{template: "templates/multiphase/controller.go.tmpl", fileName: fileName + "_controller.go"},
+			{template: "templates/multiphase/configmap_reconciler.go.tmpl", fileName: fileName + "_configmap_reconciler.go"},
+		}
+	default:
+		return nil, errors.Errorf("Reconciler type '%s' not supported, it must be '%s' or '%s'", reconcilerType, RemoteObjectKind, MultiPhaseObjectKind)
+	}
+	scaffoldFiles = append(scaffoldFiles, scaffoldFile{template: "templates/common/suite_test.go.tmpl", fileName: "suite_test.go"})
+
+	// The CRD are on config/crd/bases from the project root
+	cleanOutputDir := filepath.ToSlash(filepath.Clean(outputDir))
+	crdPath := []string{}
+	if cleanOutputDir != "." {
+		for range strings.Split(cleanOutputDir, "/") {
+			crdPath = append(crdPath, `".."`)
+		}
+	}
+	crdPath = append(crdPath, `"config"`, `"crd"`, `"bases"`)
+
+	data := scaffoldData{
+		Header:    header,
+		Package:   strings.ReplaceAll(path.Base(cleanOutputDir), "-", "_"),
+		Kind:      kind,
+		LowerKind: string(unicode.ToLower(rune(kind[0]))) + kind[1:],
+		FileName:  fileName,
+		Plural:    fileName + "s",
+		Group:     group,
+		ApiImport: apiImport,
+		CRDPath:   strings.Join(crdPath, ", "),
+	}
+	if data.Package == "." {
+		data.Package = "controllers"
+	}
+
+	files = make(map[string][]byte, len(scaffoldFiles))
+	for _, f := range scaffoldFiles {
+		t, err := template.ParseFS(scaffoldTemplates, f.template)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error when parse template %s", f.template)
+		}
+		buf := new(bytes.Buffer)
+		if err = t.Execute(buf, data); err != nil {
+			return nil, errors.Wrapf(err, "Error when execute template %s", f.template)
+		}
+		content, err := format.Source(buf.Bytes())
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error when format file %s:\n%s", f.fileName, buf.String())
+		}
+		files[f.fileName] = content
+	}
+
+	return files, nil
+}
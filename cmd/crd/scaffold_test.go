@@ -0,0 +1,146 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testScaffoldAPI is the API stub used to type check the scaffolded controllers
+const testScaffoldAPI = `package v1
+
+import (
+	"github.com/disaster37/operator-sdk-extra/pkg/apis"
+	"github.com/disaster37/operator-sdk-extra/pkg/object"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var AddToScheme = func(s *runtime.Scheme) error { return nil }
+
+type Role struct {
+	metav1.TypeMeta   ` + "`json:\",inline\"`" + `
+	metav1.ObjectMeta ` + "`json:\"metadata,omitempty\"`" + `
+	Status            apis.BasicRemoteObjectStatus ` + "`json:\"status,omitempty\"`" + `
+}
+
+func (h *Role) DeepCopyObject() runtime.Object {
+	o := &Role{TypeMeta: h.TypeMeta}
+	h.ObjectMeta.DeepCopyInto(&o.ObjectMeta)
+	h.Status.DeepCopyInto(&o.Status)
+	return o
+}
+
+func (h *Role) GetStatus() object.RemoteObjectStatus { return &h.Status }
+
+func (h *Role) GetExternalName() string { return h.GetName() }
+
+type Memcached struct {
+	metav1.TypeMeta   ` + "`json:\",inline\"`" + `
+	metav1.ObjectMeta ` + "`json:\"metadata,omitempty\"`" + `
+	Status            apis.BasicMultiPhaseObjectStatus ` + "`json:\"status,omitempty\"`" + `
+}
+
+func (h *Memcached) DeepCopyObject() runtime.Object {
+	o := &Memcached{TypeMeta: h.TypeMeta}
+	h.ObjectMeta.DeepCopyInto(&o.ObjectMeta)
+	h.Status.DeepCopyInto(&o.Status)
+	return o
+}
+
+func (h *Memcached) GetStatus() object.MultiPhaseObjectStatus { return &h.Status }
+`
+
+func TestScaffoldFiles(t *testing.T) {
+	// When remote controller
+	files, err := scaffoldFiles(RemoteObjectKind, "Role", "test.example.com", "github.com/test/operator/api/v1", "internal/controller", "// Header\n")
+	require.NoError(t, err)
+	assert.Len(t, files, 4)
+	for _, fileName := range []string{"role_controller.go", "role_reconciler.go", "role_external_reconciler.go", "suite_test.go"} {
+		if assert.Contains(t, files, fileName) {
+			f, err := parser.ParseFile(token.NewFileSet(), fileName, files[fileName], parser.ParseComments)
+			assert.NoError(t, err)
+			assert.Equal(t, "controller", f.Name.Name)
+		}
+	}
+	assert.Contains(t, string(files["role_controller.go"]), `"role.test.example.com/finalizer"`)
+	assert.Contains(t, string(files["role_controller.go"]), "resources=roles,")
+	assert.Contains(t, string(files["suite_test.go"]), `filepath.Join("..", "..", "config", "crd", "bases")`)
+
+	// When multiphase controller
+	files, err = scaffoldFiles(MultiPhaseObjectKind, "Memcached", "test.example.com", "github.com/test/operator/api/v1", "controllers", "")
+	require.NoError(t, err)
+	assert.Len(t, files, 3)
+	for _, fileName := range []string{"memcached_controller.go", "memcached_configmap_reconciler.go", "suite_test.go"} {
+		if assert.Contains(t, files, fileName) {
+			f, err := parser.ParseFile(token.NewFileSet(), fileName, files[fileName], 0)
+			assert.NoError(t, err)
+			assert.Equal(t, "controllers", f.Name.Name)
+		}
+	}
+	assert.Contains(t, string(files["memcached_controller.go"]), "newMemcachedConfigMapReconciler(client, recorder)")
+
+	// When bad parameters
+	_, err = scaffoldFiles("bad", "Role", "test.example.com", "github.com/test/operator/api/v1", "controllers", "")
+	assert.Error(t, err)
+	_, err = scaffoldFiles(RemoteObjectKind, "role", "test.example.com", "github.com/test/operator/api/v1", "controllers", "")
+	assert.Error(t, err)
+	_, err = scaffoldFiles(RemoteObjectKind, "Role", "", "github.com/test/operator/api/v1", "controllers", "")
+	assert.Error(t, err)
+	_, err = scaffoldFiles(RemoteObjectKind, "Role", "test.example.com", "", "controllers", "")
+	assert.Error(t, err)
+}
+
+// TestScaffoldFilesCompile write the scaffolded controllers on module that use this repository, and run 'go vet' on it
+func TestScaffoldFilesCompile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skip type check of scaffolded files on short mode")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+
+	// The module use the dependencies of this repository, so it not need network
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	require.NoError(t, err)
+	goMod, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	require.NoError(t, err)
+	goSum, err := os.ReadFile(filepath.Join(root, "go.sum"))
+	require.NoError(t, err)
+	goMod = []byte(strings.Replace(string(goMod), "module github.com/disaster37/operator-sdk-extra", "module github.com/test/operator", 1) +
+		"\nrequire github.com/disaster37/operator-sdk-extra v0.0.0\n\nreplace github.com/disaster37/operator-sdk-extra => " + root + "\n")
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), goMod, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.sum"), goSum, 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "api", "v1"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "api", "v1", "api.go"), []byte(testScaffoldAPI), 0644))
+
+	for _, reconcilerType := range []string{RemoteObjectKind, MultiPhaseObjectKind} {
+		kind := "Role"
+		if reconcilerType == MultiPhaseObjectKind {
+			kind = "Memcached"
+		}
+		outputDir := filepath.Join("internal", strings.ToLower(kind))
+		files, err := scaffoldFiles(reconcilerType, kind, "test.example.com", "github.com/test/operator/api/v1", outputDir, "")
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, outputDir), 0755))
+		for fileName, content := range files {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, outputDir, fileName), content, 0644))
+		}
+	}
+
+	cmd := exec.Command(goBin, "vet", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOWORK=off")
+	out, err := cmd.CombinedOutput()
+	assert.NoError(t, err, string(out))
+}
@@ -0,0 +1,60 @@
+package apis
+
+import (
+	"encoding/json"
+
+	"emperror.dev/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// ConversionDataAnnotation is the annotation where the fields unknown by the spoke version are stored during down-conversion
+	ConversionDataAnnotation = "operator-sdk-extra.webcenter.fr/conversion-data"
+)
+
+// MarshalData permit to store src, without its metadata, on the annotation of dst
+// It need to be called on ConvertFrom, to not lost the fields of hub that not exist on spoke
+func MarshalData(src metav1.Object, dst metav1.Object) (err error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(src)
+	if err != nil {
+		return errors.Wrapf(err, "Error when convert %T to unstructured", src)
+	}
+	delete(u, "metadata")
+
+	data, err := json.Marshal(u)
+	if err != nil {
+		return errors.Wrapf(err, "Error when encode %T to JSON", src)
+	}
+
+	annotations := dst.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ConversionDataAnnotation] = string(data)
+	dst.SetAnnotations(annotations)
+
+	return nil
+}
+
+// UnmarshalData permit to restore on to the object stored on the annotation of from, and to remove the annotation
+// It need to be called on ConvertTo, before copy the metadata on hub. It return false if there are no data to restore
+func UnmarshalData(from metav1.Object, to any) (isRestored bool, err error) {
+	annotations := from.GetAnnotations()
+	data, ok := annotations[ConversionDataAnnotation]
+	if !ok {
+		return false, nil
+	}
+
+	if err = json.Unmarshal([]byte(data), to); err != nil {
+		return false, errors.Wrapf(err, "Error when decode annotation %s", ConversionDataAnnotation)
+	}
+
+	delete(annotations, ConversionDataAnnotation)
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	from.SetAnnotations(annotations)
+
+	return true, nil
+}
@@ -0,0 +1,50 @@
+package apis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMarshalUnmarshalData(t *testing.T) {
+	src := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test",
+			Annotations: map[string]string{"foo": "bar"},
+		},
+		Data: map[string]string{"key": "value"},
+	}
+	dst := &corev1.Secret{}
+
+	// When there are no data
+	restored := &corev1.ConfigMap{}
+	isRestored, err := UnmarshalData(dst, restored)
+	assert.NoError(t, err)
+	assert.False(t, isRestored)
+
+	// When marshal data
+	assert.NoError(t, MarshalData(src, dst))
+	assert.Equal(t, `{"data":{"key":"value"}}`, dst.Annotations[ConversionDataAnnotation])
+
+	// When unmarshal data
+	isRestored, err = UnmarshalData(dst, restored)
+	assert.NoError(t, err)
+	assert.True(t, isRestored)
+	assert.Equal(t, map[string]string{"key": "value"}, restored.Data)
+	assert.Empty(t, restored.Name)
+	assert.Nil(t, dst.Annotations)
+
+	// When keep other annotations
+	dst.Annotations = map[string]string{"foo": "bar"}
+	assert.NoError(t, MarshalData(src, dst))
+	_, err = UnmarshalData(dst, restored)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"foo": "bar"}, dst.Annotations)
+
+	// When data is invalid
+	dst.Annotations = map[string]string{ConversionDataAnnotation: "{"}
+	_, err = UnmarshalData(dst, restored)
+	assert.Error(t, err)
+}
@@ -0,0 +1,33 @@
+package apis
+
+// TrackedDependency is an object read during the reconcile, like Secret or ConfigMap
+type TrackedDependency struct {
+
+	// Kind is the kind of object
+	Kind string `json:"kind"`
+
+	// Namespace is the namespace of object
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of object
+	Name string `json:"name"`
+}
+
+// TrackedDependencies permit to expose on status the objects read during the last reconcile
+// Embed it on your status to debug why the object is reconciled when a Secret or ConfigMap change
+type TrackedDependencies struct {
+
+	// Dependencies is the list of objects read during the last reconcile
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	Dependencies []TrackedDependency `json:"trackedDependencies,omitempty"`
+}
+
+func (h *TrackedDependencies) GetTrackedDependencies() []TrackedDependency {
+	return h.Dependencies
+}
+
+func (h *TrackedDependencies) SetTrackedDependencies(dependencies []TrackedDependency) {
+	h.Dependencies = dependencies
+}
@@ -7,3 +7,11 @@ type ConditionName string
 func (o ConditionName) String() string {
 	return string(o)
 }
+
+// ConditionReason is the reason set on condition
+type ConditionReason string
+
+// String return the condition reason as string
+func (o ConditionReason) String() string {
+	return string(o)
+}
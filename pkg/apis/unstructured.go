@@ -0,0 +1,187 @@
+package apis
+
+import (
+	"github.com/disaster37/operator-sdk-extra/pkg/apis/shared"
+	"github.com/disaster37/operator-sdk-extra/pkg/object"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// UnstructuredStatusPaths is the JSON paths of status fields on unstructured object
+// +k8s:deepcopy-gen=false
+type UnstructuredStatusPaths struct {
+	Conditions               []string
+	IsOnError                []string
+	LastErrorMessage         []string
+	ObservedGeneration       []string
+	PhaseName                []string
+	IsSync                   []string
+	LastAppliedConfiguration []string
+}
+
+// DefaultUnstructuredStatusPaths is the JSON paths used by BasicMultiPhaseObjectStatus and BasicRemoteObjectStatus
+var DefaultUnstructuredStatusPaths = UnstructuredStatusPaths{
+	Conditions:               []string{"status", "conditions"},
+	IsOnError:                []string{"status", "isOnError"},
+	LastErrorMessage:         []string{"status", "lastErrorMessage"},
+	ObservedGeneration:       []string{"status", "observedGeneration"},
+	PhaseName:                []string{"status", "phase"},
+	IsSync:                   []string{"status", "isSync"},
+	LastAppliedConfiguration: []string{"status", "lastAppliedConfiguration"},
+}
+
+// UnstructuredObjectStatus is the status accessor of unstructured object
+// It read and write the status fields directly on unstructured content from JSON paths
+// It implement MultiPhaseObjectStatus and RemoteObjectStatus interfaces
+// +k8s:deepcopy-gen=false
+type UnstructuredObjectStatus struct {
+	u     *unstructured.Unstructured
+	paths UnstructuredStatusPaths
+}
+
+// NewUnstructuredObjectStatus permit to get the status accessor of unstructured object with default JSON paths
+func NewUnstructuredObjectStatus(u *unstructured.Unstructured) *UnstructuredObjectStatus {
+	return NewUnstructuredObjectStatusWithPaths(u, DefaultUnstructuredStatusPaths)
+}
+
+// NewUnstructuredObjectStatusWithPaths permit to get the status accessor of unstructured object with custom JSON paths
+func NewUnstructuredObjectStatusWithPaths(u *unstructured.Unstructured, paths UnstructuredStatusPaths) *UnstructuredObjectStatus {
+	if u == nil {
+		panic("unstructured can't be nil")
+	}
+
+	return &UnstructuredObjectStatus{
+		u:     u,
+		paths: paths,
+	}
+}
+
+func (h *UnstructuredObjectStatus) GetConditions() []metav1.Condition {
+	rawConditions, isFound, err := unstructured.NestedSlice(h.u.Object, h.paths.Conditions...)
+	if err != nil || !isFound {
+		return nil
+	}
+
+	conditions := make([]metav1.Condition, 0, len(rawConditions))
+	for _, rawCondition := range rawConditions {
+		rawConditionMap, ok := rawCondition.(map[string]any)
+		if !ok {
+			continue
+		}
+		condition := metav1.Condition{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(rawConditionMap, &condition); err != nil {
+			continue
+		}
+		conditions = append(conditions, condition)
+	}
+
+	return conditions
+}
+
+func (h *UnstructuredObjectStatus) SetConditions(conditions []metav1.Condition) {
+	rawConditions := make([]any, 0, len(conditions))
+	for _, condition := range conditions {
+		rawCondition, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&condition)
+		if err != nil {
+			continue
+		}
+		rawConditions = append(rawConditions, rawCondition)
+	}
+
+	h.setField(rawConditions, h.paths.Conditions)
+}
+
+func (h *UnstructuredObjectStatus) GetIsOnError() bool {
+	isOnError, _, _ := unstructured.NestedBool(h.u.Object, h.paths.IsOnError...)
+	return isOnError
+}
+
+func (h *UnstructuredObjectStatus) SetIsOnError(isError bool) {
+	h.setField(isError, h.paths.IsOnError)
+}
+
+func (h *UnstructuredObjectStatus) GetLastErrorMessage() string {
+	message, _, _ := unstructured.NestedString(h.u.Object, h.paths.LastErrorMessage...)
+	return message
+}
+
+func (h *UnstructuredObjectStatus) SetLastErrorMessage(message string) {
+	h.setField(message, h.paths.LastErrorMessage)
+}
+
+func (h *UnstructuredObjectStatus) GetObservedGeneration() int64 {
+	observedGeneration, _, _ := unstructured.NestedInt64(h.u.Object, h.paths.ObservedGeneration...)
+	return observedGeneration
+}
+
+func (h *UnstructuredObjectStatus) SetObservedGeneration(version int64) {
+	h.setField(version, h.paths.ObservedGeneration)
+}
+
+func (h *UnstructuredObjectStatus) GetPhaseName() shared.PhaseName {
+	phase, _, _ := unstructured.NestedString(h.u.Object, h.paths.PhaseName...)
+	return shared.PhaseName(phase)
+}
+
+func (h *UnstructuredObjectStatus) SetPhaseName(name shared.PhaseName) {
+	h.setField(name.String(), h.paths.PhaseName)
+}
+
+func (h *UnstructuredObjectStatus) GetIsSync() bool {
+	isSync, _, _ := unstructured.NestedBool(h.u.Object, h.paths.IsSync...)
+	return isSync
+}
+
+func (h *UnstructuredObjectStatus) SetIsSync(isSync bool) {
+	h.setField(isSync, h.paths.IsSync)
+}
+
+func (h *UnstructuredObjectStatus) GetLastAppliedConfiguration() string {
+	lastAppliedConfiguration, _, _ := unstructured.NestedString(h.u.Object, h.paths.LastAppliedConfiguration...)
+	return lastAppliedConfiguration
+}
+
+func (h *UnstructuredObjectStatus) SetLastAppliedConfiguration(object string) {
+	h.setField(object, h.paths.LastAppliedConfiguration)
+}
+
+// setField permit to set the field on unstructured content
+// The content can't be on bad format because the status is only written from this accessor, so the error is ignored
+func (h *UnstructuredObjectStatus) setField(value any, path []string) {
+	if h.u.Object == nil {
+		h.u.Object = map[string]any{}
+	}
+	_ = unstructured.SetNestedField(h.u.Object, value, path...)
+}
+
+// UnstructuredMultiPhaseObject is the MultiPhaseObject that wrap unstructured object
+// Use it to handle CRD that are not compiled into your binary
+// +k8s:deepcopy-gen=false
+type UnstructuredMultiPhaseObject struct {
+	unstructured.Unstructured
+}
+
+// NewUnstructuredMultiPhaseObject permit to get empty unstructured multi phase object of GVK
+func NewUnstructuredMultiPhaseObject(gvk schema.GroupVersionKind) *UnstructuredMultiPhaseObject {
+	o := &UnstructuredMultiPhaseObject{}
+	o.SetGroupVersionKind(gvk)
+
+	return o
+}
+
+func (h *UnstructuredMultiPhaseObject) GetStatus() object.MultiPhaseObjectStatus {
+	return NewUnstructuredObjectStatus(&h.Unstructured)
+}
+
+func (h *UnstructuredMultiPhaseObject) DeepCopyObject() runtime.Object {
+	return &UnstructuredMultiPhaseObject{
+		Unstructured: *h.Unstructured.DeepCopy(),
+	}
+}
+
+// NewEmptyInstance permit to get empty instance of the same GVK
+func (h *UnstructuredMultiPhaseObject) NewEmptyInstance() runtime.Unstructured {
+	return NewUnstructuredMultiPhaseObject(h.GroupVersionKind())
+}
@@ -0,0 +1,82 @@
+package apis
+
+import (
+	"testing"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/object"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestUnstructuredObjectStatus(t *testing.T) {
+	u := &unstructured.Unstructured{}
+	var status object.RemoteObjectStatus = NewUnstructuredObjectStatus(u)
+
+	// With empty object
+	assert.Empty(t, status.GetConditions())
+	assert.False(t, status.GetIsOnError())
+	assert.False(t, status.GetIsSync())
+	assert.Empty(t, status.GetLastErrorMessage())
+	assert.Empty(t, status.GetLastAppliedConfiguration())
+	assert.Equal(t, int64(0), status.GetObservedGeneration())
+
+	// When set fields
+	status.SetIsOnError(true)
+	status.SetIsSync(true)
+	status.SetLastErrorMessage("test error")
+	status.SetLastAppliedConfiguration("{}")
+	status.SetObservedGeneration(2)
+	status.SetConditions([]metav1.Condition{
+		{
+			Type:               "Ready",
+			Status:             metav1.ConditionTrue,
+			Reason:             "Ready",
+			LastTransitionTime: metav1.Now().Rfc3339Copy(),
+		},
+	})
+
+	assert.True(t, status.GetIsOnError())
+	assert.True(t, status.GetIsSync())
+	assert.Equal(t, "test error", status.GetLastErrorMessage())
+	assert.Equal(t, "{}", status.GetLastAppliedConfiguration())
+	assert.Equal(t, int64(2), status.GetObservedGeneration())
+	assert.Len(t, status.GetConditions(), 1)
+	assert.Equal(t, metav1.ConditionTrue, status.GetConditions()[0].Status)
+
+	// Fields are on unstructured content
+	message, _, _ := unstructured.NestedString(u.Object, "status", "lastErrorMessage")
+	assert.Equal(t, "test error", message)
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	assert.Len(t, conditions, 1)
+
+	// Content can be deep copied
+	assert.Equal(t, u.Object, u.DeepCopy().Object)
+
+	// When custom paths
+	paths := DefaultUnstructuredStatusPaths
+	paths.PhaseName = []string{"status", "state"}
+	multiPhaseStatus := NewUnstructuredObjectStatusWithPaths(u, paths)
+	multiPhaseStatus.SetPhaseName("running")
+	assert.Equal(t, "running", multiPhaseStatus.GetPhaseName().String())
+	state, _, _ := unstructured.NestedString(u.Object, "status", "state")
+	assert.Equal(t, "running", state)
+}
+
+func TestUnstructuredMultiPhaseObject(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Test"}
+	var o object.MultiPhaseObject = NewUnstructuredMultiPhaseObject(gvk)
+	o.SetName("test")
+
+	assert.Equal(t, gvk, o.GetObjectKind().GroupVersionKind())
+
+	o.GetStatus().SetPhaseName("running")
+	assert.Equal(t, "running", o.GetStatus().GetPhaseName().String())
+
+	// When deep copy
+	copied := o.DeepCopyObject().(*UnstructuredMultiPhaseObject)
+	copied.GetStatus().SetPhaseName("other")
+	assert.Equal(t, "running", o.GetStatus().GetPhaseName().String())
+	assert.Equal(t, "test", copied.GetName())
+}
@@ -97,3 +97,38 @@ func (in *MapAny) DeepCopy() *MapAny {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrackedDependencies) DeepCopyInto(out *TrackedDependencies) {
+	*out = *in
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]TrackedDependency, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrackedDependencies.
+func (in *TrackedDependencies) DeepCopy() *TrackedDependencies {
+	if in == nil {
+		return nil
+	}
+	out := new(TrackedDependencies)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrackedDependency) DeepCopyInto(out *TrackedDependency) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrackedDependency.
+func (in *TrackedDependency) DeepCopy() *TrackedDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(TrackedDependency)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,331 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	crcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// KubeconfigSecretKey is the key of kubeconfig secret that contain the kubeconfig of cluster
+	KubeconfigSecretKey = "kubeconfig"
+
+	// KubeconfigSecretAlternativeKey is the key used by Cluster API on kubeconfig secret
+	KubeconfigSecretAlternativeKey = "value"
+)
+
+var (
+	// KubeconfigNotReadyRetryAfter is the delay to retry when the kubeconfig secret not yet exist
+	KubeconfigNotReadyRetryAfter = 30 * time.Second
+
+	// ClusterCacheSyncTimeout is the max duration to wait the cache of cluster is synced before return its client
+	ClusterCacheSyncTimeout = 30 * time.Second
+
+	// ErrClusterClientProviderNotStarted is returned when the provider is used before the manager start it
+	ErrClusterClientProviderNotStarted = errors.Sentinel("Cluster client provider is not started, add it on manager with mgr.Add")
+)
+
+type targetClientKey struct{}
+
+// WithTargetClient permit to store on context the client of the cluster where the children are managed
+func WithTargetClient(ctx context.Context, c client.Client) context.Context {
+	return context.WithValue(ctx, targetClientKey{}, c)
+}
+
+// TargetClientFromContext permit to get the client of the cluster where the children are managed
+// It return nil when the children are managed on the same cluster
+func TargetClientFromContext(ctx context.Context) client.Client {
+	c, _ := ctx.Value(targetClientKey{}).(client.Client)
+	return c
+}
+
+// TargetClusterReconcilerAction is optional interface of reconciler actions to manage the children on another cluster
+// The multiphase step reconciler call it after 'configure', and the remote reconciler before 'getRemoteHandler'. They put the client on context with WithTargetClient
+type TargetClusterReconcilerAction interface {
+
+	// GetTargetClient permit to get the client of cluster where the children are managed
+	// It return nil client to manage the children on the same cluster
+	GetTargetClient(ctx context.Context, o client.Object, logger *logrus.Entry) (targetClient client.Client, err error)
+}
+
+// ClusterClientProvider permit to get the clients of other clusters from their kubeconfig secret
+// It need to be added on manager with mgr.Add, so the clusters are started and stopped with the manager
+type ClusterClientProvider interface {
+	manager.Runnable
+	manager.LeaderElectionRunnable
+
+	// GetClient permit to get the client of cluster from the kubeconfig secret
+	// The client is returned when the cache of cluster is synced, and it is cached until the secret change
+	GetClient(ctx context.Context, kubeconfigSecret types.NamespacedName) (c client.Client, err error)
+
+	// Watch permit to watch the objects on cluster and to enqueue requests on controller with the event handler
+	// It can be called on each reconcile, the watch is only added one time per controller and object type
+	Watch(ctx context.Context, kubeconfigSecret types.NamespacedName, c crcontroller.Controller, o client.Object, eventHandler handler.EventHandler) (err error)
+
+	// Invalidate permit to stop and remove the cluster from cache
+	Invalidate(kubeconfigSecret types.NamespacedName)
+}
+
+// BasicClusterClientProvider is the basic implementation of ClusterClientProvider interface
+// It start one cluster (client and cache) per kubeconfig secret
+type BasicClusterClientProvider struct {
+	client     client.Client
+	scheme     *runtime.Scheme
+	mutex      sync.Mutex
+	ctx        context.Context
+	clusters   map[types.NamespacedName]*remoteCluster
+	newCluster func(config *rest.Config, scheme *runtime.Scheme) (cluster.Cluster, error)
+}
+
+// remoteCluster is the cluster started from kubeconfig secret
+// It is stored on cache as soon as it start, so the other callers wait on ready instead of starting it again
+type remoteCluster struct {
+	resourceVersion string
+	cluster         cluster.Cluster
+	cancel          context.CancelFunc
+	watches         map[string]bool
+	ready           chan struct{}
+	err             error
+}
+
+// NewBasicClusterClientProvider is the basic constructor of ClusterClientProvider interface
+// The client is used to read the kubeconfig secrets, and the scheme is used by the clients of clusters
+func NewBasicClusterClientProvider(client client.Client, scheme *runtime.Scheme) ClusterClientProvider {
+	if client == nil {
+		panic("client can't be nil")
+	}
+	if scheme == nil {
+		panic("scheme can't be nil")
+	}
+
+	return &BasicClusterClientProvider{
+		client:   client,
+		scheme:   scheme,
+		clusters: map[types.NamespacedName]*remoteCluster{},
+		newCluster: func(config *rest.Config, scheme *runtime.Scheme) (cluster.Cluster, error) {
+			return cluster.New(config, func(o *cluster.Options) {
+				o.Scheme = scheme
+			})
+		},
+	}
+}
+
+// Start permit to run the provider until the manager stop
+// The clusters are started with the context of manager, so they are stopped with it
+func (h *BasicClusterClientProvider) Start(ctx context.Context) error {
+	h.mutex.Lock()
+	h.ctx = ctx
+	h.mutex.Unlock()
+
+	<-ctx.Done()
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for kubeconfigSecret := range h.clusters {
+		h.invalidate(kubeconfigSecret)
+	}
+	h.ctx = nil
+
+	return nil
+}
+
+// NeedLeaderElection permit to start the provider on all replicas, like the caches
+func (h *BasicClusterClientProvider) NeedLeaderElection() bool {
+	return false
+}
+
+func (h *BasicClusterClientProvider) GetClient(ctx context.Context, kubeconfigSecret types.NamespacedName) (c client.Client, err error) {
+	remote, err := h.getCluster(ctx, kubeconfigSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	return remote.cluster.GetClient(), nil
+}
+
+func (h *BasicClusterClientProvider) Watch(ctx context.Context, kubeconfigSecret types.NamespacedName, c crcontroller.Controller, o client.Object, eventHandler handler.EventHandler) (err error) {
+	remote, err := h.getCluster(ctx, kubeconfigSecret)
+	if err != nil {
+		return err
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	key := fmt.Sprintf("%p/%T", c, o)
+	if remote.watches[key] {
+		return nil
+	}
+	if err = c.Watch(source.Kind(remote.cluster.GetCache(), o, eventHandler)); err != nil {
+		return errors.Wrapf(err, "Error when watch %T on cluster from secret %s", o, kubeconfigSecret.String())
+	}
+	remote.watches[key] = true
+
+	return nil
+}
+
+func (h *BasicClusterClientProvider) Invalidate(kubeconfigSecret types.NamespacedName) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.invalidate(kubeconfigSecret)
+}
+
+func (h *BasicClusterClientProvider) invalidate(kubeconfigSecret types.NamespacedName) {
+	if remote, ok := h.clusters[kubeconfigSecret]; ok {
+		remote.cancel()
+		delete(h.clusters, kubeconfigSecret)
+	}
+}
+
+// getCluster permit to get the cluster from cache, or to start it if the kubeconfig secret change
+func (h *BasicClusterClientProvider) getCluster(ctx context.Context, kubeconfigSecret types.NamespacedName) (remote *remoteCluster, err error) {
+	secret := &corev1.Secret{}
+	if err = h.client.Get(ctx, kubeconfigSecret, secret); err != nil {
+		if k8serrors.IsNotFound(err) {
+			h.Invalidate(kubeconfigSecret)
+			return nil, NewDependencyNotReadyError(errors.Errorf("Kubeconfig secret %s not found", kubeconfigSecret.String()), KubeconfigNotReadyRetryAfter)
+		}
+		return nil, errors.Wrapf(err, "Error when get kubeconfig secret %s", kubeconfigSecret.String())
+	}
+
+	h.mutex.Lock()
+	if h.ctx == nil {
+		h.mutex.Unlock()
+		return nil, ErrClusterClientProviderNotStarted
+	}
+	if remote, ok := h.clusters[kubeconfigSecret]; ok && remote.resourceVersion == secret.ResourceVersion {
+		h.mutex.Unlock()
+		return h.waitCluster(ctx, kubeconfigSecret, remote)
+	}
+	h.invalidate(kubeconfigSecret)
+
+	// The cluster is started without lock, because it can take ClusterCacheSyncTimeout and it must not block the other clusters
+	clusterCtx, cancel := context.WithCancel(h.ctx)
+	remote = &remoteCluster{
+		resourceVersion: secret.ResourceVersion,
+		cancel:          cancel,
+		watches:         map[string]bool{},
+		ready:           make(chan struct{}),
+	}
+	h.clusters[kubeconfigSecret] = remote
+	h.mutex.Unlock()
+
+	if err = h.startCluster(ctx, clusterCtx, kubeconfigSecret, secret, remote); err != nil {
+		cancel()
+		h.mutex.Lock()
+		if current, ok := h.clusters[kubeconfigSecret]; ok && current == remote {
+			delete(h.clusters, kubeconfigSecret)
+		}
+		h.mutex.Unlock()
+	}
+	remote.err = err
+	close(remote.ready)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return remote, nil
+}
+
+// waitCluster permit to wait the cluster started by another caller is ready
+func (h *BasicClusterClientProvider) waitCluster(ctx context.Context, kubeconfigSecret types.NamespacedName, remote *remoteCluster) (*remoteCluster, error) {
+	select {
+	case <-remote.ready:
+		if remote.err != nil {
+			return nil, remote.err
+		}
+		return remote, nil
+	case <-ctx.Done():
+		return nil, errors.Wrapf(ctx.Err(), "Error when wait cluster from secret %s is started", kubeconfigSecret.String())
+	}
+}
+
+// startCluster permit to create and start the cluster with the context of provider, and to wait its cache is synced
+// The cluster is removed from cache when it stop, for example when the credentials are wrong
+func (h *BasicClusterClientProvider) startCluster(ctx context.Context, clusterCtx context.Context, kubeconfigSecret types.NamespacedName, secret *corev1.Secret, remote *remoteCluster) (err error) {
+	config, err := RESTConfigFromKubeconfigSecret(secret)
+	if err != nil {
+		return NewTerminalError(err)
+	}
+	remote.cluster, err = h.newCluster(config, h.scheme)
+	if err != nil {
+		return errors.Wrapf(err, "Error when create cluster from secret %s", kubeconfigSecret.String())
+	}
+	cancel := remote.cancel
+
+	startErr := make(chan error, 1)
+	go func() {
+		err := remote.cluster.Start(clusterCtx)
+		if err != nil {
+			logrus.Errorf("Error when start cluster from secret %s: %s", kubeconfigSecret.String(), err.Error())
+		}
+		startErr <- err
+
+		h.mutex.Lock()
+		defer h.mutex.Unlock()
+		if current, ok := h.clusters[kubeconfigSecret]; ok && current == remote {
+			delete(h.clusters, kubeconfigSecret)
+		}
+		cancel()
+	}()
+
+	syncCtx, syncCancel := context.WithTimeout(ctx, ClusterCacheSyncTimeout)
+	defer syncCancel()
+	isSynced := make(chan bool, 1)
+	go func() {
+		isSynced <- remote.cluster.GetCache().WaitForCacheSync(syncCtx)
+	}()
+
+	select {
+	case err = <-startErr:
+		cancel()
+		if err == nil {
+			err = errors.New("Cluster stopped")
+		}
+		return errors.Wrapf(err, "Error when start cluster from secret %s", kubeconfigSecret.String())
+	case ok := <-isSynced:
+		if !ok {
+			cancel()
+			return errors.Errorf("Timeout when wait cache of cluster from secret %s is synced", kubeconfigSecret.String())
+		}
+	}
+
+	return nil
+}
+
+// RESTConfigFromKubeconfigSecret permit to get the rest config from the kubeconfig stored on secret
+// It read the key KubeconfigSecretKey, or KubeconfigSecretAlternativeKey if not exist
+func RESTConfigFromKubeconfigSecret(secret *corev1.Secret) (config *rest.Config, err error) {
+	kubeconfig, ok := secret.Data[KubeconfigSecretKey]
+	if !ok {
+		if kubeconfig, ok = secret.Data[KubeconfigSecretAlternativeKey]; !ok {
+			return nil, errors.Errorf("Kubeconfig secret %s/%s need to have the key '%s' or '%s'", secret.Namespace, secret.Name, KubeconfigSecretKey, KubeconfigSecretAlternativeKey)
+		}
+	}
+
+	config, err = clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error when read kubeconfig from secret %s/%s", secret.Namespace, secret.Name)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,328 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/disaster37/operator-sdk-extra/pkg/apis"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://127.0.0.1:1
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+current-context: test
+users:
+- name: test
+  user:
+    token: test
+`
+
+func TestTargetClientFromContext(t *testing.T) {
+	defaultClient := fake.NewClientBuilder().Build()
+	targetClient := fake.NewClientBuilder().Build()
+	action := NewBasicReconcilerAction(defaultClient, record.NewFakeRecorder(10), "TestReady")
+
+	// When no target client
+	assert.Nil(t, TargetClientFromContext(context.Background()))
+	assert.Equal(t, action.Client(), action.TargetClient(context.Background()))
+
+	// When target client
+	ctx := WithTargetClient(context.Background(), targetClient)
+	assert.Equal(t, targetClient, TargetClientFromContext(ctx))
+	assert.Equal(t, targetClient, action.TargetClient(ctx))
+}
+
+func TestRESTConfigFromKubeconfigSecret(t *testing.T) {
+	// With key kubeconfig
+	config, err := RESTConfigFromKubeconfigSecret(&corev1.Secret{Data: map[string][]byte{KubeconfigSecretKey: []byte(testKubeconfig)}})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://127.0.0.1:1", config.Host)
+
+	// With key of Cluster API
+	_, err = RESTConfigFromKubeconfigSecret(&corev1.Secret{Data: map[string][]byte{KubeconfigSecretAlternativeKey: []byte(testKubeconfig)}})
+	assert.NoError(t, err)
+
+	// When key not exist
+	_, err = RESTConfigFromKubeconfigSecret(&corev1.Secret{})
+	assert.Error(t, err)
+
+	// When kubeconfig is invalid
+	_, err = RESTConfigFromKubeconfigSecret(&corev1.Secret{Data: map[string][]byte{KubeconfigSecretKey: []byte("bad")}})
+	assert.Error(t, err)
+}
+
+func TestBasicClusterClientProvider(t *testing.T) {
+	key := types.NamespacedName{Namespace: "default", Name: "kubeconfig"}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		Data:       map[string][]byte{KubeconfigSecretKey: []byte(testKubeconfig)},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(secret).Build()
+	provider := NewBasicClusterClientProvider(fakeClient, scheme.Scheme)
+	assert.False(t, provider.NeedLeaderElection())
+
+	// When provider is not started
+	_, err := provider.GetClient(context.Background(), key)
+	assert.ErrorIs(t, err, ErrClusterClientProviderNotStarted)
+
+	// When secret exist
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		assert.NoError(t, provider.Start(ctx))
+		close(stopped)
+	}()
+	var c client.Client
+	assert.Eventually(t, func() bool {
+		c, err = provider.GetClient(context.Background(), key)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NotNil(t, c)
+
+	// When secret not change, client is cached
+	c2, err := provider.GetClient(context.Background(), key)
+	assert.NoError(t, err)
+	assert.Same(t, c, c2)
+
+	// When secret change, new client is created
+	assert.NoError(t, fakeClient.Get(context.Background(), key, secret))
+	secret.Labels = map[string]string{"foo": "bar"}
+	assert.NoError(t, fakeClient.Update(context.Background(), secret))
+	c3, err := provider.GetClient(context.Background(), key)
+	assert.NoError(t, err)
+	assert.NotSame(t, c, c3)
+
+	// When kubeconfig is invalid
+	secret.Data[KubeconfigSecretKey] = []byte("bad")
+	assert.NoError(t, fakeClient.Update(context.Background(), secret))
+	_, err = provider.GetClient(context.Background(), key)
+	assert.Error(t, err)
+	assert.True(t, IsTerminalError(err))
+
+	// When secret not exist
+	_, err = provider.GetClient(context.Background(), types.NamespacedName{Namespace: "default", Name: "foo"})
+	assert.Error(t, err)
+	assert.True(t, IsDependencyNotReadyError(err))
+
+	// When manager stop, clusters are removed
+	secret.Data[KubeconfigSecretKey] = []byte(testKubeconfig)
+	assert.NoError(t, fakeClient.Update(context.Background(), secret))
+	_, err = provider.GetClient(context.Background(), key)
+	assert.NoError(t, err)
+	cancel()
+	<-stopped
+	assert.Empty(t, provider.(*BasicClusterClientProvider).clusters)
+	_, err = provider.GetClient(context.Background(), key)
+	assert.ErrorIs(t, err, ErrClusterClientProviderNotStarted)
+}
+
+type testFailingCluster struct {
+	cluster.Cluster
+}
+
+func (h *testFailingCluster) Start(ctx context.Context) error {
+	return errors.New("bad credentials")
+}
+
+func TestBasicClusterClientProviderWhenStartFail(t *testing.T) {
+	key := types.NamespacedName{Namespace: "default", Name: "kubeconfig"}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		Data:       map[string][]byte{KubeconfigSecretKey: []byte(testKubeconfig)},
+	}
+	provider := NewBasicClusterClientProvider(fake.NewClientBuilder().WithObjects(secret).Build(), scheme.Scheme).(*BasicClusterClientProvider)
+	isFailing := true
+	provider.newCluster = func(config *rest.Config, scheme *runtime.Scheme) (cluster.Cluster, error) {
+		c, err := cluster.New(config, func(o *cluster.Options) {
+			o.Scheme = scheme
+		})
+		if err != nil || !isFailing {
+			return c, err
+		}
+		return &testFailingCluster{Cluster: c}, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = provider.Start(ctx)
+	}()
+	assert.Eventually(t, func() bool {
+		_, err := provider.GetClient(context.Background(), key)
+		return !errors.Is(err, ErrClusterClientProviderNotStarted)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// When cluster can't start, it is not cached
+	_, err := provider.GetClient(context.Background(), key)
+	assert.ErrorContains(t, err, "bad credentials")
+	assert.Empty(t, provider.clusters)
+
+	// When cluster can start
+	isFailing = false
+	_, err = provider.GetClient(context.Background(), key)
+	assert.NoError(t, err)
+	assert.Len(t, provider.clusters, 1)
+}
+
+type testNotSyncedCluster struct {
+	cluster.Cluster
+}
+
+func (h *testNotSyncedCluster) GetCache() cache.Cache {
+	return &testNotSyncedCache{Cache: h.Cluster.GetCache()}
+}
+
+type testNotSyncedCache struct {
+	cache.Cache
+}
+
+func (h *testNotSyncedCache) WaitForCacheSync(ctx context.Context) bool {
+	<-ctx.Done()
+	return false
+}
+
+func TestBasicClusterClientProviderWhenCacheNotSynced(t *testing.T) {
+	slowKey := types.NamespacedName{Namespace: "default", Name: "slow"}
+	key := types.NamespacedName{Namespace: "default", Name: "kubeconfig"}
+	fakeClient := fake.NewClientBuilder().WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: slowKey.Name, Namespace: slowKey.Namespace},
+			Data:       map[string][]byte{KubeconfigSecretKey: []byte(strings.ReplaceAll(testKubeconfig, "127.0.0.1:1", "127.0.0.1:2"))},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Data:       map[string][]byte{KubeconfigSecretKey: []byte(testKubeconfig)},
+		},
+	).Build()
+	provider := NewBasicClusterClientProvider(fakeClient, scheme.Scheme).(*BasicClusterClientProvider)
+	provider.newCluster = func(config *rest.Config, scheme *runtime.Scheme) (cluster.Cluster, error) {
+		c, err := cluster.New(config, func(o *cluster.Options) {
+			o.Scheme = scheme
+		})
+		if err != nil || config.Host != "https://127.0.0.1:2" {
+			return c, err
+		}
+		return &testNotSyncedCluster{Cluster: c}, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = provider.Start(ctx)
+	}()
+	assert.Eventually(t, func() bool {
+		_, err := provider.GetClient(context.Background(), key)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	provider.Invalidate(key)
+
+	// When the cache of one cluster is never synced
+	slowCtx, slowCancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer slowCancel()
+	slowErr := make(chan error, 1)
+	go func() {
+		_, err := provider.GetClient(slowCtx, slowKey)
+		slowErr <- err
+	}()
+	assert.Eventually(t, func() bool {
+		provider.mutex.Lock()
+		defer provider.mutex.Unlock()
+		_, ok := provider.clusters[slowKey]
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The other clusters are not blocked
+	getErr := make(chan error, 1)
+	go func() {
+		_, err := provider.GetClient(context.Background(), key)
+		getErr <- err
+	}()
+	select {
+	case err := <-getErr:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		assert.Fail(t, "GetClient is blocked by the cluster not synced")
+	}
+
+	// The cluster not synced is not cached
+	assert.ErrorContains(t, <-slowErr, "Timeout")
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+	assert.NotContains(t, provider.clusters, slowKey)
+	assert.Contains(t, provider.clusters, key)
+}
+
+type testTargetClusterStepAction struct {
+	MultiPhaseStepReconcilerAction
+	targetClient client.Client
+}
+
+func (h *testTargetClusterStepAction) GetTargetClient(ctx context.Context, o client.Object, logger *logrus.Entry) (client.Client, error) {
+	return h.targetClient, nil
+}
+
+func TestMultiPhaseStepActionWithTargetClient(t *testing.T) {
+	managementClient := fake.NewClientBuilder().Build()
+	targetClient := fake.NewClientBuilder().Build()
+	action := NewBasicMultiPhaseStepReconcilerAction(managementClient, "test", "TestReady", record.NewFakeRecorder(10))
+	o := apis.NewUnstructuredMultiPhaseObject(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Test"})
+	o.SetName("parent")
+	o.SetNamespace("default")
+	o.SetUID("uid")
+
+	// When the type of children is not set to delete them with parent
+	ctx := WithTargetClient(context.Background(), targetClient)
+	child := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: "default"}}
+	_, err := action.Create(ctx, o, map[string]any{}, []client.Object{child}, logrus.NewEntry(logrus.New()))
+	assert.Error(t, err)
+	assert.True(t, IsTerminalError(err))
+	assert.Error(t, targetClient.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "child"}, &corev1.ConfigMap{}))
+
+	// Children are created on target cluster with tracking reference
+	action.(*BasicMultiPhaseStepReconcilerAction).SetTrackedChildrenLists(&corev1.SecretList{}, &corev1.ConfigMapList{})
+	assert.Equal(t, []client.ObjectList{&corev1.SecretList{}, &corev1.ConfigMapList{}}, action.GetTrackedChildrenLists())
+	child = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: "default"}}
+	_, err = action.Create(ctx, o, map[string]any{}, []client.Object{child}, logrus.NewEntry(logrus.New()))
+	assert.NoError(t, err)
+
+	child = &corev1.ConfigMap{}
+	assert.NoError(t, targetClient.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "child"}, child))
+	assert.Empty(t, child.OwnerReferences)
+	assert.Equal(t, "uid", child.Labels[ParentUIDLabel])
+	assert.Equal(t, "parent", child.Annotations[ParentNameAnnotation])
+	assert.Equal(t, "Test.example.com", child.Annotations[ParentGroupKindAnnotation])
+	assert.Error(t, managementClient.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "child"}, &corev1.ConfigMap{}))
+
+	// Children are deleted on target cluster
+	_, err = action.Delete(ctx, o, map[string]any{}, []client.Object{child}, logrus.NewEntry(logrus.New()))
+	assert.NoError(t, err)
+	assert.Error(t, targetClient.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "child"}, &corev1.ConfigMap{}))
+
+	// The step action can give the target client
+	var targetAction any = &testTargetClusterStepAction{MultiPhaseStepReconcilerAction: action, targetClient: targetClient}
+	_, ok := targetAction.(TargetClusterReconcilerAction)
+	assert.True(t, ok)
+}
@@ -1,11 +1,7 @@
 package controller
 
 import (
-	"reflect"
-
 	"github.com/disaster37/operator-sdk-extra/pkg/apis/shared"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
@@ -15,29 +11,3 @@ const (
 	BaseAnnotation string               = "operator-sdk-extra.webcenter.fr"
 	ShortenError   int                  = 100
 )
-
-func getObjectMeta(r client.Object) metav1.ObjectMeta {
-	rt := reflect.TypeOf(r)
-	if rt.Kind() != reflect.Ptr {
-		panic("Resource must be pointer")
-	}
-	rv := reflect.ValueOf(r).Elem()
-	om := rv.FieldByName("ObjectMeta")
-	if !om.IsValid() {
-		panic("Resouce must have field ObjectMeta")
-	}
-	return om.Interface().(metav1.ObjectMeta)
-}
-
-func getObjectStatus(r client.Object) any {
-	rt := reflect.TypeOf(r)
-	if rt.Kind() != reflect.Ptr {
-		panic("Resource must be pointer")
-	}
-	rv := reflect.ValueOf(r).Elem()
-	om := rv.FieldByName("Status")
-	if !om.IsValid() {
-		return nil
-	}
-	return om.Interface()
-}
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/apis/shared"
+	"github.com/disaster37/operator-sdk-extra/pkg/object"
+	condition "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	InitializeReason  shared.ConditionReason = "Initialize"
+	SuccessReason     shared.ConditionReason = "Success"
+	ReadyReason       shared.ConditionReason = "Ready"
+	FailedReason      shared.ConditionReason = "Failed"
+	ProgressingReason shared.ConditionReason = "Progressing"
+)
+
+// SetCondition permit to set condition on status
+// It stamp the ObservedGeneration and let LastTransitionTime unchanged if status is the same
+// It return true if the condition has changed
+func SetCondition(status object.ObjectStatus, generation int64, newCondition metav1.Condition) (changed bool) {
+	conditions := status.GetConditions()
+	newCondition.ObservedGeneration = generation
+	changed = condition.SetStatusCondition(&conditions, newCondition)
+	status.SetConditions(conditions)
+
+	return changed
+}
+
+// GetCondition permit to get condition from status
+// It return nil if condition not exist
+func GetCondition(status object.ObjectStatus, conditionName shared.ConditionName) *metav1.Condition {
+	return condition.FindStatusCondition(status.GetConditions(), conditionName.String())
+}
+
+// IsConditionTrue permit to know if condition exist and is true
+func IsConditionTrue(status object.ObjectStatus, conditionName shared.ConditionName) bool {
+	return condition.IsStatusConditionTrue(status.GetConditions(), conditionName.String())
+}
+
+// ConditionAggregator permit to compute the top level condition (like Ready) from sub conditions
+type ConditionAggregator interface {
+
+	// GetConditionName permit to get the top level condition name
+	GetConditionName() shared.ConditionName
+
+	// GetSubConditionNames permit to get the sub conditions used to compute the top level condition
+	GetSubConditionNames() []shared.ConditionName
+
+	// AddSubConditionNames permit to add sub conditions used to compute the top level condition
+	AddSubConditionNames(conditionNames ...shared.ConditionName)
+
+	// Aggregate permit to compute the top level condition from sub conditions and set it on status
+	// - True if all sub conditions are true
+	// - False if one sub condition is false, the message list the failing conditions
+	// - Unknown if one sub condition is unknown or not yet exist, the message list the pending conditions
+	Aggregate(status object.ObjectStatus, generation int64) metav1.Condition
+}
+
+// BasicConditionAggregator is the basic implementation of ConditionAggregator interface
+type BasicConditionAggregator struct {
+	conditionName     shared.ConditionName
+	subConditionNames []shared.ConditionName
+}
+
+// NewBasicConditionAggregator is the basic constructor of ConditionAggregator interface
+func NewBasicConditionAggregator(conditionName shared.ConditionName, subConditionNames ...shared.ConditionName) ConditionAggregator {
+	if conditionName == "" {
+		panic("Condition name must be provided")
+	}
+
+	return &BasicConditionAggregator{
+		conditionName:     conditionName,
+		subConditionNames: subConditionNames,
+	}
+}
+
+func (h *BasicConditionAggregator) GetConditionName() shared.ConditionName {
+	return h.conditionName
+}
+
+func (h *BasicConditionAggregator) GetSubConditionNames() []shared.ConditionName {
+	return h.subConditionNames
+}
+
+func (h *BasicConditionAggregator) AddSubConditionNames(conditionNames ...shared.ConditionName) {
+	for _, conditionName := range conditionNames {
+		if conditionName == h.conditionName {
+			continue
+		}
+		isFound := false
+		for _, subConditionName := range h.subConditionNames {
+			if subConditionName == conditionName {
+				isFound = true
+				break
+			}
+		}
+		if !isFound {
+			h.subConditionNames = append(h.subConditionNames, conditionName)
+		}
+	}
+}
+
+func (h *BasicConditionAggregator) Aggregate(status object.ObjectStatus, generation int64) metav1.Condition {
+	failedConditions := make([]string, 0)
+	pendingConditions := make([]string, 0)
+
+	for _, subConditionName := range h.subConditionNames {
+		subCondition := GetCondition(status, subConditionName)
+		switch {
+		case subCondition == nil || subCondition.Status == metav1.ConditionUnknown:
+			pendingConditions = append(pendingConditions, subConditionName.String())
+		case subCondition.Status == metav1.ConditionFalse:
+			if subCondition.Message != "" {
+				failedConditions = append(failedConditions, fmt.Sprintf("%s (%s)", subConditionName.String(), subCondition.Message))
+			} else {
+				failedConditions = append(failedConditions, subConditionName.String())
+			}
+		}
+	}
+
+	aggregatedCondition := metav1.Condition{
+		Type:   h.conditionName.String(),
+		Status: metav1.ConditionTrue,
+		Reason: ReadyReason.String(),
+	}
+
+	if len(failedConditions) > 0 {
+		aggregatedCondition.Status = metav1.ConditionFalse
+		aggregatedCondition.Reason = FailedReason.String()
+		aggregatedCondition.Message = fmt.Sprintf("Failing conditions: %s", strings.Join(failedConditions, ", "))
+	} else if len(pendingConditions) > 0 {
+		aggregatedCondition.Status = metav1.ConditionUnknown
+		aggregatedCondition.Reason = ProgressingReason.String()
+		aggregatedCondition.Message = fmt.Sprintf("Waiting conditions: %s", strings.Join(pendingConditions, ", "))
+	}
+
+	SetCondition(status, generation, aggregatedCondition)
+
+	return *GetCondition(status, h.conditionName)
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/apis/shared"
+	"github.com/disaster37/operator-sdk-extra/pkg/object"
+	condition "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ConditionManager permit to manage the conditions of object status
+// It always write the conditions on status, stamp the ObservedGeneration from object
+// and only change the LastTransitionTime when the condition status change
+// The message is always formatted with fmt.Sprintf, so use "%s" to set message that can contain '%'
+type ConditionManager interface {
+
+	// Init permit to init the condition to false with reason Initialize if not yet exist
+	Init(conditionName shared.ConditionName)
+
+	// MarkTrue permit to set the condition to true
+	MarkTrue(conditionName shared.ConditionName, reason shared.ConditionReason, messageFormat string, messageArgs ...any)
+
+	// MarkFalse permit to set the condition to false
+	MarkFalse(conditionName shared.ConditionName, reason shared.ConditionReason, messageFormat string, messageArgs ...any)
+
+	// MarkUnknown permit to set the condition to unknown
+	MarkUnknown(conditionName shared.ConditionName, reason shared.ConditionReason, messageFormat string, messageArgs ...any)
+
+	// Remove permit to remove the condition
+	Remove(conditionName shared.ConditionName)
+
+	// Get permit to get the condition. It return nil if not exist
+	Get(conditionName shared.ConditionName) *metav1.Condition
+
+	// IsTrue permit to know if condition exist and is true
+	IsTrue(conditionName shared.ConditionName) bool
+}
+
+// BasicConditionManager is the basic implementation of ConditionManager interface
+type BasicConditionManager struct {
+	o      client.Object
+	status object.ObjectStatus
+}
+
+// NewBasicConditionManager is the basic constructor of ConditionManager interface
+// The status must be the status of object o
+func NewBasicConditionManager(o client.Object, status object.ObjectStatus) ConditionManager {
+	if o == nil {
+		panic("Object can't be nil")
+	}
+	if status == nil {
+		panic("Status can't be nil")
+	}
+
+	return &BasicConditionManager{
+		o:      o,
+		status: status,
+	}
+}
+
+func (h *BasicConditionManager) Init(conditionName shared.ConditionName) {
+	if h.Get(conditionName) == nil {
+		h.set(conditionName, metav1.ConditionFalse, InitializeReason, "")
+	}
+}
+
+func (h *BasicConditionManager) MarkTrue(conditionName shared.ConditionName, reason shared.ConditionReason, messageFormat string, messageArgs ...any) {
+	h.set(conditionName, metav1.ConditionTrue, reason, messageFormat, messageArgs...)
+}
+
+func (h *BasicConditionManager) MarkFalse(conditionName shared.ConditionName, reason shared.ConditionReason, messageFormat string, messageArgs ...any) {
+	h.set(conditionName, metav1.ConditionFalse, reason, messageFormat, messageArgs...)
+}
+
+func (h *BasicConditionManager) MarkUnknown(conditionName shared.ConditionName, reason shared.ConditionReason, messageFormat string, messageArgs ...any) {
+	h.set(conditionName, metav1.ConditionUnknown, reason, messageFormat, messageArgs...)
+}
+
+func (h *BasicConditionManager) Remove(conditionName shared.ConditionName) {
+	conditions := h.status.GetConditions()
+	if condition.RemoveStatusCondition(&conditions, conditionName.String()) {
+		h.status.SetConditions(conditions)
+	}
+}
+
+func (h *BasicConditionManager) Get(conditionName shared.ConditionName) *metav1.Condition {
+	return GetCondition(h.status, conditionName)
+}
+
+func (h *BasicConditionManager) IsTrue(conditionName shared.ConditionName) bool {
+	return IsConditionTrue(h.status, conditionName)
+}
+
+func (h *BasicConditionManager) set(conditionName shared.ConditionName, status metav1.ConditionStatus, reason shared.ConditionReason, messageFormat string, messageArgs ...any) {
+	SetCondition(h.status, h.o.GetGeneration(), metav1.Condition{
+		Type:    conditionName.String(),
+		Status:  status,
+		Reason:  reason.String(),
+		Message: fmt.Sprintf(messageFormat, messageArgs...),
+	})
+}
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/disaster37/operator-sdk-extra/pkg/apis"
+	"github.com/disaster37/operator-sdk-extra/pkg/object"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type testMultiPhaseObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Status            apis.BasicMultiPhaseObjectStatus `json:"status,omitempty"`
+}
+
+func (h *testMultiPhaseObject) DeepCopyObject() runtime.Object {
+	o := &testMultiPhaseObject{
+		TypeMeta: h.TypeMeta,
+	}
+	h.ObjectMeta.DeepCopyInto(&o.ObjectMeta)
+	h.Status.DeepCopyInto(&o.Status)
+	return o
+}
+func (h *testMultiPhaseObject) GetStatus() object.MultiPhaseObjectStatus { return &h.Status }
+
+func TestBasicConditionManager(t *testing.T) {
+	o := &testMultiPhaseObject{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test",
+			Generation: 3,
+		},
+	}
+	manager := NewBasicConditionManager(o, o.GetStatus())
+
+	// When init
+	manager.Init("test")
+	c := manager.Get("test")
+	assert.NotNil(t, c)
+	assert.Equal(t, metav1.ConditionFalse, c.Status)
+	assert.Equal(t, InitializeReason.String(), c.Reason)
+	assert.Equal(t, int64(3), c.ObservedGeneration)
+
+	// When init and condition already exist
+	manager.MarkTrue("test", ReadyReason, "Ready")
+	manager.Init("test")
+	assert.True(t, manager.IsTrue("test"))
+	lastTransitionTime := manager.Get("test").LastTransitionTime
+
+	// When mark true again, LastTransitionTime not change
+	o.Generation = 4
+	manager.MarkTrue("test", SuccessReason, "Ready %d", 4)
+	c = manager.Get("test")
+	assert.Equal(t, lastTransitionTime, c.LastTransitionTime)
+	assert.Equal(t, "Ready 4", c.Message)
+	assert.Equal(t, SuccessReason.String(), c.Reason)
+	assert.Equal(t, int64(4), c.ObservedGeneration)
+
+	// When mark false
+	manager.MarkFalse("test", FailedReason, "100%% failed")
+	c = manager.Get("test")
+	assert.Equal(t, metav1.ConditionFalse, c.Status)
+	assert.Equal(t, "100% failed", c.Message)
+
+	// When mark unknown
+	manager.MarkUnknown("test", ProgressingReason, "")
+	assert.Equal(t, metav1.ConditionUnknown, manager.Get("test").Status)
+
+	// When remove
+	manager.Remove("test")
+	assert.Nil(t, manager.Get("test"))
+	assert.Empty(t, o.Status.Conditions)
+}
+
+func TestBasicMultiPhaseStepReconcilerActionConditions(t *testing.T) {
+	o := &testMultiPhaseObject{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test",
+			Generation: 1,
+		},
+	}
+	action := NewBasicMultiPhaseStepReconcilerAction(fake.NewClientBuilder().Build(), "test", "TestReady", record.NewFakeRecorder(10))
+	logger := logrus.NewEntry(logrus.New())
+
+	// When configure
+	_, err := action.Configure(context.Background(), ctrl.Request{}, o, logger)
+	assert.NoError(t, err)
+	assert.NotNil(t, GetCondition(o.GetStatus(), "TestReady"))
+	assert.Equal(t, "test", o.GetStatus().GetPhaseName().String())
+
+	// When error
+	_, err = action.OnError(context.Background(), o, nil, errors.New("boom"), logger)
+	assert.Error(t, err)
+	c := GetCondition(o.GetStatus(), "TestReady")
+	assert.Equal(t, metav1.ConditionFalse, c.Status)
+	assert.Equal(t, FailedReason.String(), c.Reason)
+	assert.Equal(t, "boom", c.Message)
+
+	// When success
+	_, err = action.OnSuccess(context.Background(), o, nil, NewBasicMultiPhaseDiff(), logger)
+	assert.NoError(t, err)
+	assert.True(t, IsConditionTrue(o.GetStatus(), "TestReady"))
+}
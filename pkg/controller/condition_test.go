@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/apis"
+	"github.com/disaster37/operator-sdk-extra/pkg/apis/shared"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSetCondition(t *testing.T) {
+	status := &apis.BasicObjectStatus{}
+
+	// When condition not exist
+	assert.True(t, SetCondition(status, 1, metav1.Condition{
+		Type:   "test",
+		Status: metav1.ConditionFalse,
+		Reason: InitializeReason.String(),
+	}))
+	c := GetCondition(status, "test")
+	assert.NotNil(t, c)
+	assert.Equal(t, int64(1), c.ObservedGeneration)
+	assert.False(t, c.LastTransitionTime.IsZero())
+	assert.False(t, IsConditionTrue(status, "test"))
+
+	// When condition is updated
+	assert.True(t, SetCondition(status, 2, metav1.Condition{
+		Type:   "test",
+		Status: metav1.ConditionTrue,
+		Reason: ReadyReason.String(),
+	}))
+	c = GetCondition(status, "test")
+	assert.Equal(t, int64(2), c.ObservedGeneration)
+	assert.True(t, IsConditionTrue(status, "test"))
+
+	// When nothink change
+	assert.False(t, SetCondition(status, 2, metav1.Condition{
+		Type:   "test",
+		Status: metav1.ConditionTrue,
+		Reason: ReadyReason.String(),
+	}))
+
+	// When condition not exist
+	assert.Nil(t, GetCondition(status, "other"))
+}
+
+func TestBasicConditionAggregatorSubConditions(t *testing.T) {
+	aggregator := NewBasicConditionAggregator(ReadyCondition, "step1")
+
+	assert.Equal(t, ReadyCondition, aggregator.GetConditionName())
+	assert.Equal(t, []shared.ConditionName{"step1"}, aggregator.GetSubConditionNames())
+
+	// Ignore duplicate and top level condition
+	aggregator.AddSubConditionNames("step1", "step2", ReadyCondition)
+	assert.Equal(t, []shared.ConditionName{"step1", "step2"}, aggregator.GetSubConditionNames())
+}
+
+func TestBasicConditionAggregatorAggregate(t *testing.T) {
+	aggregator := NewBasicConditionAggregator(ReadyCondition, "step1", "step2")
+	status := &apis.BasicObjectStatus{}
+
+	// When sub conditions not yet exist
+	c := aggregator.Aggregate(status, 1)
+	assert.Equal(t, metav1.ConditionUnknown, c.Status)
+	assert.Equal(t, ProgressingReason.String(), c.Reason)
+	assert.Equal(t, "Waiting conditions: step1, step2", c.Message)
+	assert.Equal(t, int64(1), c.ObservedGeneration)
+
+	// When one sub condition failed
+	SetCondition(status, 1, metav1.Condition{
+		Type:   "step1",
+		Status: metav1.ConditionTrue,
+		Reason: ReadyReason.String(),
+	})
+	SetCondition(status, 1, metav1.Condition{
+		Type:    "step2",
+		Status:  metav1.ConditionFalse,
+		Reason:  FailedReason.String(),
+		Message: "boom",
+	})
+	c = aggregator.Aggregate(status, 1)
+	assert.Equal(t, metav1.ConditionFalse, c.Status)
+	assert.Equal(t, FailedReason.String(), c.Reason)
+	assert.Equal(t, "Failing conditions: step2 (boom)", c.Message)
+
+	// When all sub conditions are true
+	SetCondition(status, 2, metav1.Condition{
+		Type:   "step2",
+		Status: metav1.ConditionTrue,
+		Reason: ReadyReason.String(),
+	})
+	c = aggregator.Aggregate(status, 2)
+	assert.Equal(t, metav1.ConditionTrue, c.Status)
+	assert.Equal(t, ReadyReason.String(), c.Reason)
+	assert.Empty(t, c.Message)
+	assert.Equal(t, int64(2), c.ObservedGeneration)
+	assert.True(t, IsConditionTrue(status, ReadyCondition))
+}
+
+func TestBasicMultiPhaseReconcilerActionOnSuccess(t *testing.T) {
+	action := NewBasicMultiPhaseReconcilerAction(fake.NewClientBuilder().Build(), ReadyCondition, record.NewFakeRecorder(10), "step1", "step2")
+	o := apis.NewUnstructuredMultiPhaseObject(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Test"})
+	o.SetGeneration(1)
+	o.GetStatus().SetPhaseName(StartingPhase)
+	o.GetStatus().SetIsOnError(true)
+	logger := logrus.NewEntry(logrus.New())
+
+	// When one sub condition is false, the phase is kept and it requeue
+	SetCondition(o.GetStatus(), 1, metav1.Condition{Type: "step1", Status: metav1.ConditionTrue, Reason: ReadyReason.String()})
+	SetCondition(o.GetStatus(), 1, metav1.Condition{Type: "step2", Status: metav1.ConditionFalse, Reason: FailedReason.String(), Message: "boom"})
+	res, err := action.OnSuccess(context.Background(), o, map[string]any{}, logger)
+	assert.NoError(t, err)
+	assert.Equal(t, ConditionNotReadyRetryAfter, res.RequeueAfter)
+	assert.Equal(t, StartingPhase, o.GetStatus().GetPhaseName())
+	assert.True(t, o.GetStatus().GetIsOnError())
+	assert.False(t, IsConditionTrue(o.GetStatus(), ReadyCondition))
+
+	// When all sub conditions are true
+	SetCondition(o.GetStatus(), 1, metav1.Condition{Type: "step2", Status: metav1.ConditionTrue, Reason: ReadyReason.String()})
+	res, err = action.OnSuccess(context.Background(), o, map[string]any{}, logger)
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+	assert.Equal(t, RunningPhase, o.GetStatus().GetPhaseName())
+	assert.False(t, o.GetStatus().GetIsOnError())
+	assert.True(t, IsConditionTrue(o.GetStatus(), ReadyCondition))
+}
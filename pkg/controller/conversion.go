@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"emperror.dev/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	webhookconversion "sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+)
+
+// ValidateConversion permit to check that the kind of hub can be converted between all its versions registered on scheme
+// The hub need to implement conversion.Hub, and all other versions (the spokes) need to implement conversion.Convertible
+func ValidateConversion(scheme *runtime.Scheme, hub conversion.Hub) (err error) {
+	isConvertible, err := webhookconversion.IsConvertible(scheme, hub)
+	if err != nil {
+		return errors.Wrapf(err, "Error when check conversion of %T", hub)
+	}
+	if !isConvertible {
+		return errors.Errorf("Kind of %T can't be converted, all its versions need to be registered on scheme", hub)
+	}
+
+	return nil
+}
+
+// NewConversionWebhookRegister permit to register the conversion webhook of the kind of hub
+// It need to be called one time per kind, with the hub version. The spokes are discovered from the manager scheme
+func NewConversionWebhookRegister(hub conversion.Hub) WebhookRegister {
+	return func(mgr ctrl.Manager, client client.Client) (err error) {
+		if err = ValidateConversion(mgr.GetScheme(), hub); err != nil {
+			return err
+		}
+
+		if err = ctrl.NewWebhookManagedBy(mgr).For(hub).Complete(); err != nil {
+			return errors.Wrapf(err, "Error when register conversion webhook of %T", hub)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+type testConversionHub struct {
+	corev1.ConfigMap
+}
+
+func (h *testConversionHub) Hub() {}
+
+type testConversionSpoke struct {
+	corev1.ConfigMap
+}
+
+func (h *testConversionSpoke) ConvertTo(dst conversion.Hub) error {
+	return nil
+}
+
+func (h *testConversionSpoke) ConvertFrom(src conversion.Hub) error {
+	return nil
+}
+
+type testConversionNotSpoke struct {
+	corev1.ConfigMap
+}
+
+func TestValidateConversion(t *testing.T) {
+	hubGVK := schema.GroupVersionKind{Group: "example.com", Version: "v2", Kind: "Test"}
+	spokeGVK := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Test"}
+
+	// When hub and spoke are registered
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypeWithName(hubGVK, &testConversionHub{})
+	scheme.AddKnownTypeWithName(spokeGVK, &testConversionSpoke{})
+	assert.NoError(t, ValidateConversion(scheme, &testConversionHub{}))
+
+	// When only hub is registered
+	scheme = runtime.NewScheme()
+	scheme.AddKnownTypeWithName(hubGVK, &testConversionHub{})
+	assert.Error(t, ValidateConversion(scheme, &testConversionHub{}))
+
+	// When other version is not a spoke
+	scheme = runtime.NewScheme()
+	scheme.AddKnownTypeWithName(hubGVK, &testConversionHub{})
+	scheme.AddKnownTypeWithName(spokeGVK, &testConversionNotSpoke{})
+	assert.Error(t, ValidateConversion(scheme, &testConversionHub{}))
+
+	// When hub is not registered
+	assert.Error(t, ValidateConversion(runtime.NewScheme(), &testConversionHub{}))
+}
@@ -0,0 +1,352 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+
+	"emperror.dev/errors"
+	"github.com/disaster37/operator-sdk-extra/pkg/apis"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// TrackedDependencyGroupKinds is the group kinds of objects recorded by the tracking client when they are read during reconcile
+var TrackedDependencyGroupKinds = []schema.GroupKind{
+	{Kind: "Secret"},
+	{Kind: "ConfigMap"},
+}
+
+// TrackedDependenciesSetter is the interface that status can implement to expose the tracked dependencies
+// You can embed apis.TrackedDependencies on your status to implement it
+type TrackedDependenciesSetter interface {
+	SetTrackedDependencies(dependencies []apis.TrackedDependency)
+}
+
+type dependencyTrackerKey struct{}
+
+// DependencyTracker record the dependencies read during one reconcile
+type DependencyTracker struct {
+	mutex        sync.Mutex
+	dependencies map[trackedObjectKey]bool
+}
+
+// trackedObjectKey is the key of parent or dependency
+type trackedObjectKey struct {
+	GroupKind schema.GroupKind
+	types.NamespacedName
+}
+
+// WithDependencyTracker permit to store new dependency tracker on context
+func WithDependencyTracker(ctx context.Context) (context.Context, *DependencyTracker) {
+	tracker := &DependencyTracker{
+		dependencies: map[trackedObjectKey]bool{},
+	}
+
+	return context.WithValue(ctx, dependencyTrackerKey{}, tracker), tracker
+}
+
+// DependencyTrackerFromContext permit to get the dependency tracker from context
+// It return nil if not found
+func DependencyTrackerFromContext(ctx context.Context) *DependencyTracker {
+	tracker, _ := ctx.Value(dependencyTrackerKey{}).(*DependencyTracker)
+	return tracker
+}
+
+// Track permit to record dependency
+func (h *DependencyTracker) Track(groupKind schema.GroupKind, key types.NamespacedName) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.dependencies[trackedObjectKey{GroupKind: groupKind, NamespacedName: key}] = true
+}
+
+// Dependencies permit to get the recorded dependencies, sorted by kind, namespace and name
+func (h *DependencyTracker) Dependencies() []apis.TrackedDependency {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	dependencies := make([]apis.TrackedDependency, 0, len(h.dependencies))
+	for key := range h.dependencies {
+		dependencies = append(dependencies, apis.TrackedDependency{
+			Kind:      key.GroupKind.String(),
+			Namespace: key.Namespace,
+			Name:      key.Name,
+		})
+	}
+	sort.Slice(dependencies, func(i, j int) bool {
+		if dependencies[i].Kind != dependencies[j].Kind {
+			return dependencies[i].Kind < dependencies[j].Kind
+		}
+		if dependencies[i].Namespace != dependencies[j].Namespace {
+			return dependencies[i].Namespace < dependencies[j].Namespace
+		}
+		return dependencies[i].Name < dependencies[j].Name
+	})
+
+	return dependencies
+}
+
+// keys permit to get the recorded dependencies as keys
+func (h *DependencyTracker) keys() []trackedObjectKey {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	keys := make([]trackedObjectKey, 0, len(h.dependencies))
+	for key := range h.dependencies {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// DependencyRegistry store the dependencies of each parent, to find the parents to reconcile when dependency change
+type DependencyRegistry struct {
+	mutex      sync.RWMutex
+	parents    map[trackedObjectKey][]trackedObjectKey
+	dependents map[trackedObjectKey]map[trackedObjectKey]bool
+}
+
+// NewDependencyRegistry permit to get empty dependency registry
+func NewDependencyRegistry() *DependencyRegistry {
+	return &DependencyRegistry{
+		parents:    map[trackedObjectKey][]trackedObjectKey{},
+		dependents: map[trackedObjectKey]map[trackedObjectKey]bool{},
+	}
+}
+
+// Set permit to replace the dependencies of parent
+func (h *DependencyRegistry) Set(parentGroupKind schema.GroupKind, parent types.NamespacedName, tracker *DependencyTracker) {
+	parentKey := trackedObjectKey{GroupKind: parentGroupKind, NamespacedName: parent}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.remove(parentKey)
+	keys := tracker.keys()
+	if len(keys) == 0 {
+		return
+	}
+	h.parents[parentKey] = keys
+	for _, key := range keys {
+		if h.dependents[key] == nil {
+			h.dependents[key] = map[trackedObjectKey]bool{}
+		}
+		h.dependents[key][parentKey] = true
+	}
+}
+
+// Remove permit to remove the dependencies of parent, when it's deleted
+func (h *DependencyRegistry) Remove(parentGroupKind schema.GroupKind, parent types.NamespacedName) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.remove(trackedObjectKey{GroupKind: parentGroupKind, NamespacedName: parent})
+}
+
+func (h *DependencyRegistry) remove(parentKey trackedObjectKey) {
+	for _, key := range h.parents[parentKey] {
+		delete(h.dependents[key], parentKey)
+		if len(h.dependents[key]) == 0 {
+			delete(h.dependents, key)
+		}
+	}
+	delete(h.parents, parentKey)
+}
+
+// Parents permit to get the parents of group kind that depend on the object
+func (h *DependencyRegistry) Parents(parentGroupKind schema.GroupKind, groupKind schema.GroupKind, key types.NamespacedName) []types.NamespacedName {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	parents := make([]types.NamespacedName, 0)
+	for parentKey := range h.dependents[trackedObjectKey{GroupKind: groupKind, NamespacedName: key}] {
+		if parentKey.GroupKind == parentGroupKind {
+			parents = append(parents, parentKey.NamespacedName)
+		}
+	}
+	sort.Slice(parents, func(i, j int) bool {
+		return parents[i].String() < parents[j].String()
+	})
+
+	return parents
+}
+
+// TrackedDependencyMapFunc permit to get the parents of group kind that read the dependency during their last reconcile
+func TrackedDependencyMapFunc(registry *DependencyRegistry, parentGroupKind schema.GroupKind, dependencyGroupKind schema.GroupKind) handler.MapFunc {
+	return func(ctx context.Context, o client.Object) []reconcile.Request {
+		parents := registry.Parents(parentGroupKind, dependencyGroupKind, types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()})
+		requests := make([]reconcile.Request, 0, len(parents))
+		for _, parent := range parents {
+			requests = append(requests, reconcile.Request{NamespacedName: parent})
+		}
+
+		return requests
+	}
+}
+
+// NewTrackedDependencyEventHandler permit to get the event handler that enqueue the parents of group kind that read the dependency
+// The registry is the one of reconciler, see DependencyRegistry on reconcilers
+func NewTrackedDependencyEventHandler(registry *DependencyRegistry, parentGroupKind schema.GroupKind, dependencyGroupKind schema.GroupKind) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(TrackedDependencyMapFunc(registry, parentGroupKind, dependencyGroupKind))
+}
+
+// SetupTrackedDependenciesWithManager permit to watch the dependencies on controller builder, to reconcile the parent when the objects it read change
+// The registry is the one of reconciler, see DependencyRegistry on reconcilers. It watch Secret and ConfigMap when dependencies is empty
+func SetupTrackedDependenciesWithManager(mgr ctrl.Manager, b *builder.Builder, registry *DependencyRegistry, parent client.Object, dependencies ...client.Object) (err error) {
+	if registry == nil {
+		return errors.New("Registry can't be nil")
+	}
+	parentGVK, err := apiutil.GVKForObject(parent, mgr.GetScheme())
+	if err != nil {
+		return errors.Wrap(err, "Error when get GVK of parent")
+	}
+	if len(dependencies) == 0 {
+		dependencies = []client.Object{&corev1.Secret{}, &corev1.ConfigMap{}}
+	}
+
+	for _, dependency := range dependencies {
+		gvk, err := apiutil.GVKForObject(dependency, mgr.GetScheme())
+		if err != nil {
+			return errors.Wrapf(err, "Error when get GVK of dependency %T", dependency)
+		}
+		b.Watches(dependency, NewTrackedDependencyEventHandler(registry, parentGVK.GroupKind(), gvk.GroupKind()))
+	}
+
+	return nil
+}
+
+// trackingClient is client that record on the dependency tracker of context the tracked dependencies that are read
+type trackingClient struct {
+	client.Client
+}
+
+// NewTrackingClient permit to wrap the client to record the objects of TrackedDependencyGroupKinds read during reconcile
+// The objects read with Get (even not found) and the items returned by List are recorded, only when the context have a dependency tracker
+// The objects created after the List that match it are not recorded
+func NewTrackingClient(c client.Client) client.Client {
+	if _, ok := c.(*trackingClient); ok {
+		return c
+	}
+
+	return &trackingClient{Client: c}
+}
+
+func (h *trackingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	err := h.Client.Get(ctx, key, obj, opts...)
+
+	// Not found objects are also tracked, to reconcile when they are created
+	if tracker := DependencyTrackerFromContext(ctx); tracker != nil && (err == nil || k8serrors.IsNotFound(err)) {
+		if groupKind, isTracked := h.trackedGroupKind(obj); isTracked {
+			tracker.Track(groupKind, key)
+		}
+	}
+
+	return err
+}
+
+func (h *trackingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if err := h.Client.List(ctx, list, opts...); err != nil {
+		return err
+	}
+
+	tracker := DependencyTrackerFromContext(ctx)
+	if tracker == nil {
+		return nil
+	}
+
+	return meta.EachListItem(list, func(item runtime.Object) error {
+		obj, ok := item.(client.Object)
+		if !ok {
+			return nil
+		}
+		if groupKind, isTracked := h.trackedGroupKind(obj); isTracked {
+			tracker.Track(groupKind, client.ObjectKeyFromObject(obj))
+		}
+		return nil
+	})
+}
+
+// trackedGroupKind permit to know if the object need to be tracked
+func (h *trackingClient) trackedGroupKind(obj client.Object) (groupKind schema.GroupKind, isTracked bool) {
+	if h.Client.Scheme() == nil {
+		return groupKind, false
+	}
+	gvk, err := apiutil.GVKForObject(obj, h.Client.Scheme())
+	if err != nil {
+		return groupKind, false
+	}
+	for _, trackedGroupKind := range TrackedDependencyGroupKinds {
+		if gvk.GroupKind() == trackedGroupKind {
+			return trackedGroupKind, true
+		}
+	}
+
+	return groupKind, false
+}
+
+// trackDependencies permit to start to record the dependencies read during the reconcile of o
+// The returned function need to be deferred before the status update. It register the dependencies and expose them on status
+func trackDependencies(ctx context.Context, c client.Client, registry *DependencyRegistry, o client.Object, logger *logrus.Entry) (context.Context, func()) {
+	ctx, tracker := WithDependencyTracker(ctx)
+
+	return ctx, func() {
+		if c.Scheme() == nil {
+			return
+		}
+		parentGVK, err := apiutil.GVKForObject(o, c.Scheme())
+		if err != nil {
+			logger.Warnf("Can't register tracked dependencies: %s", err.Error())
+			return
+		}
+		registry.Set(parentGVK.GroupKind(), types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}, tracker)
+
+		if setter := getTrackedDependenciesSetter(o); setter != nil {
+			setter.SetTrackedDependencies(tracker.Dependencies())
+		}
+	}
+}
+
+// getTrackedDependenciesSetter permit to get the setter of tracked dependencies from object or from its field 'Status'
+// It return nil when not implemented
+func getTrackedDependenciesSetter(o client.Object) TrackedDependenciesSetter {
+	if setter, ok := o.(TrackedDependenciesSetter); ok {
+		return setter
+	}
+
+	v := reflect.ValueOf(o)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	status := v.Elem().FieldByName("Status")
+	if !status.IsValid() || !status.CanAddr() {
+		return nil
+	}
+	setter, _ := status.Addr().Interface().(TrackedDependenciesSetter)
+
+	return setter
+}
+
+// untrackDependencies permit to remove the dependencies of deleted object
+func untrackDependencies(c client.Client, registry *DependencyRegistry, o client.Object, key types.NamespacedName) {
+	if c.Scheme() == nil {
+		return
+	}
+	parentGVK, err := apiutil.GVKForObject(o, c.Scheme())
+	if err != nil {
+		return
+	}
+	registry.Remove(parentGVK.GroupKind(), key)
+}
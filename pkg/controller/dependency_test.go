@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/apis"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type testDependencyStatus struct {
+	apis.TrackedDependencies `json:",inline"`
+}
+
+type testDependencyObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Status            testDependencyStatus `json:"status,omitempty"`
+}
+
+func (h *testDependencyObject) DeepCopyObject() runtime.Object {
+	o := &testDependencyObject{TypeMeta: h.TypeMeta}
+	h.ObjectMeta.DeepCopyInto(&o.ObjectMeta)
+	h.Status.TrackedDependencies.DeepCopyInto(&o.Status.TrackedDependencies)
+	return o
+}
+
+func TestTrackingClient(t *testing.T) {
+	c := NewTrackingClient(fake.NewClientBuilder().WithObjects(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: "default"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"}},
+	).Build())
+
+	// When already wrapped
+	assert.Equal(t, c, NewTrackingClient(c))
+
+	// When context have no tracker
+	assert.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "secret"}, &corev1.Secret{}))
+
+	// When context have tracker
+	ctx, tracker := WithDependencyTracker(context.Background())
+	assert.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "secret"}, &corev1.Secret{}))
+	assert.Error(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "cm"}, &corev1.ConfigMap{}))
+	assert.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "pod"}, &corev1.Pod{}))
+	assert.Equal(t, tracker, DependencyTrackerFromContext(ctx))
+	assert.Equal(t, []apis.TrackedDependency{
+		{Kind: "ConfigMap", Namespace: "default", Name: "cm"},
+		{Kind: "Secret", Namespace: "default", Name: "secret"},
+	}, tracker.Dependencies())
+
+	// When list
+	ctx, tracker = WithDependencyTracker(context.Background())
+	assert.NoError(t, c.List(ctx, &corev1.SecretList{}))
+	assert.NoError(t, c.List(ctx, &corev1.PodList{}))
+	assert.Equal(t, []apis.TrackedDependency{{Kind: "Secret", Namespace: "default", Name: "secret"}}, tracker.Dependencies())
+}
+
+func TestDependencyRegistry(t *testing.T) {
+	registry := NewDependencyRegistry()
+	parentGroupKind := schema.GroupKind{Group: "example.com", Kind: "Test"}
+	otherGroupKind := schema.GroupKind{Group: "example.com", Kind: "Other"}
+	secretGroupKind := schema.GroupKind{Kind: "Secret"}
+	secret := types.NamespacedName{Namespace: "default", Name: "secret"}
+
+	_, tracker := WithDependencyTracker(context.Background())
+	tracker.Track(secretGroupKind, secret)
+	registry.Set(parentGroupKind, types.NamespacedName{Namespace: "default", Name: "test1"}, tracker)
+	registry.Set(parentGroupKind, types.NamespacedName{Namespace: "default", Name: "test2"}, tracker)
+	registry.Set(otherGroupKind, types.NamespacedName{Namespace: "default", Name: "test3"}, tracker)
+
+	// When dependency is used by parents
+	assert.Equal(t, []types.NamespacedName{{Namespace: "default", Name: "test1"}, {Namespace: "default", Name: "test2"}}, registry.Parents(parentGroupKind, secretGroupKind, secret))
+	assert.Empty(t, registry.Parents(parentGroupKind, schema.GroupKind{Kind: "ConfigMap"}, secret))
+
+	// When mapper is called
+	requests := TrackedDependencyMapFunc(registry, otherGroupKind, secretGroupKind)(context.Background(), &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: "default"}})
+	assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test3"}}}, requests)
+
+	// When parent not use dependency anymore
+	_, emptyTracker := WithDependencyTracker(context.Background())
+	registry.Set(parentGroupKind, types.NamespacedName{Namespace: "default", Name: "test1"}, emptyTracker)
+	assert.Equal(t, []types.NamespacedName{{Namespace: "default", Name: "test2"}}, registry.Parents(parentGroupKind, secretGroupKind, secret))
+
+	// When parent is removed
+	registry.Remove(parentGroupKind, types.NamespacedName{Namespace: "default", Name: "test2"})
+	assert.Empty(t, registry.Parents(parentGroupKind, secretGroupKind, secret))
+	assert.Len(t, registry.dependents, 1)
+	registry.Remove(otherGroupKind, types.NamespacedName{Namespace: "default", Name: "test3"})
+	assert.Empty(t, registry.dependents)
+	assert.Empty(t, registry.parents)
+}
+
+func TestTrackDependencies(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "TestDependency"}
+	s := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(s))
+	s.AddKnownTypeWithName(gvk, &testDependencyObject{})
+	c := NewTrackingClient(fake.NewClientBuilder().WithScheme(s).Build())
+	o := &testDependencyObject{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	key := types.NamespacedName{Namespace: "default", Name: "test"}
+	registry := NewDependencyRegistry()
+
+	ctx, registerDependencies := trackDependencies(context.Background(), c, registry, o, logrus.NewEntry(logrus.New()))
+	_ = c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "secret"}, &corev1.Secret{})
+	registerDependencies()
+
+	// Dependencies are exposed on status
+	assert.Equal(t, []apis.TrackedDependency{{Kind: "Secret", Namespace: "default", Name: "secret"}}, o.Status.Dependencies)
+
+	// Dependencies are registered
+	assert.Equal(t, []types.NamespacedName{key}, registry.Parents(gvk.GroupKind(), schema.GroupKind{Kind: "Secret"}, types.NamespacedName{Namespace: "default", Name: "secret"}))
+
+	// When object is deleted
+	untrackDependencies(c, registry, &testDependencyObject{}, key)
+	assert.Empty(t, registry.Parents(gvk.GroupKind(), schema.GroupKind{Kind: "Secret"}, types.NamespacedName{Namespace: "default", Name: "secret"}))
+}
@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/disaster37/operator-sdk-extra/pkg/apis/shared"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	TerminalErrorReason      shared.ConditionReason = "TerminalError"
+	DependencyNotReadyReason shared.ConditionReason = "DependencyNotReady"
+
+	// DefaultDependencyRetryAfter is the delay used to requeue when dependency is not ready and no delay is provided
+	DefaultDependencyRetryAfter time.Duration = 30 * time.Second
+)
+
+// TerminalError is an error that will never succeed by retrying, like validation error
+// The reconciler set the condition and stop to requeue the object
+type TerminalError struct {
+	err error
+}
+
+// NewTerminalError permit to wrap error as terminal error
+func NewTerminalError(err error) error {
+	return &TerminalError{
+		err: err,
+	}
+}
+
+func (e *TerminalError) Error() string {
+	if e.err == nil {
+		return "terminal error"
+	}
+	return e.err.Error()
+}
+
+func (e *TerminalError) Unwrap() error {
+	return e.err
+}
+
+// TransientError is an error that can succeed after waiting
+// The reconciler requeue the object after the retryAfter delay instead of exponential backoff
+type TransientError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+// NewTransientError permit to wrap error as transient error
+// If retryAfter is 0, the controller-runtime exponential backoff is used
+func NewTransientError(err error, retryAfter time.Duration) error {
+	return &TransientError{
+		err:        err,
+		retryAfter: retryAfter,
+	}
+}
+
+func (e *TransientError) Error() string {
+	if e.err == nil {
+		return "transient error"
+	}
+	return e.err.Error()
+}
+
+func (e *TransientError) Unwrap() error {
+	return e.err
+}
+
+// RetryAfter return the delay before retry
+func (e *TransientError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// DependencyNotReadyError is an error when a dependency is not yet ready (like remote cluster not yet deployed)
+// The reconciler requeue the object after the retryAfter delay, without warning event
+type DependencyNotReadyError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+// NewDependencyNotReadyError permit to wrap error as dependency not ready error
+// If retryAfter is 0, it use DefaultDependencyRetryAfter
+func NewDependencyNotReadyError(err error, retryAfter time.Duration) error {
+	if retryAfter <= 0 {
+		retryAfter = DefaultDependencyRetryAfter
+	}
+
+	return &DependencyNotReadyError{
+		err:        err,
+		retryAfter: retryAfter,
+	}
+}
+
+func (e *DependencyNotReadyError) Error() string {
+	if e.err == nil {
+		return "dependency not ready"
+	}
+	return e.err.Error()
+}
+
+func (e *DependencyNotReadyError) Unwrap() error {
+	return e.err
+}
+
+// RetryAfter return the delay before retry
+func (e *DependencyNotReadyError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// IsTerminalError permit to know if error is terminal error
+// It also handle the terminal error from controller-runtime
+func IsTerminalError(err error) bool {
+	var terminalError *TerminalError
+	if errors.As(err, &terminalError) {
+		return true
+	}
+
+	return errors.Is(err, reconcile.TerminalError(nil))
+}
+
+// IsDependencyNotReadyError permit to know if error is dependency not ready error
+func IsDependencyNotReadyError(err error) bool {
+	var dependencyNotReadyError *DependencyNotReadyError
+	return errors.As(err, &dependencyNotReadyError)
+}
+
+// GetRetryAfter permit to get the retry delay of transient or dependency not ready error
+// It return false if error is not one of them or if no delay is provided
+func GetRetryAfter(err error) (retryAfter time.Duration, ok bool) {
+	var (
+		dependencyNotReadyError *DependencyNotReadyError
+		transientError          *TransientError
+	)
+
+	if errors.As(err, &dependencyNotReadyError) {
+		return dependencyNotReadyError.RetryAfter(), true
+	}
+	if errors.As(err, &transientError) && transientError.RetryAfter() > 0 {
+		return transientError.RetryAfter(), true
+	}
+
+	return 0, false
+}
+
+// GetConditionReasonFromError permit to get the condition reason to set from error
+func GetConditionReasonFromError(err error) shared.ConditionReason {
+	switch {
+	case IsTerminalError(err):
+		return TerminalErrorReason
+	case IsDependencyNotReadyError(err):
+		return DependencyNotReadyReason
+	default:
+		return FailedReason
+	}
+}
+
+// ResultFromError permit to compute the result and the error to return to controller-runtime from the error
+// - terminal error: the error is returned as controller-runtime terminal error to stop requeue
+// - dependency not ready or transient error with delay: the object is requeued after the delay without error
+// - others: the error is returned to use exponential backoff
+func ResultFromError(currentErr error) (res ctrl.Result, err error) {
+	if currentErr == nil {
+		return res, nil
+	}
+
+	if IsTerminalError(currentErr) {
+		if errors.Is(currentErr, reconcile.TerminalError(nil)) {
+			return res, currentErr
+		}
+		return res, reconcile.TerminalError(currentErr)
+	}
+
+	if retryAfter, ok := GetRetryAfter(currentErr); ok {
+		return ctrl.Result{RequeueAfter: retryAfter}, nil
+	}
+
+	return res, currentErr
+}
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestErrorClassification(t *testing.T) {
+	// When standard error
+	err := errors.New("test")
+	assert.False(t, IsTerminalError(err))
+	assert.False(t, IsDependencyNotReadyError(err))
+	_, ok := GetRetryAfter(err)
+	assert.False(t, ok)
+	assert.Equal(t, FailedReason, GetConditionReasonFromError(err))
+
+	// When terminal error wrapped
+	err = errors.Wrap(NewTerminalError(errors.New("test")), "wrap")
+	assert.True(t, IsTerminalError(err))
+	assert.Equal(t, "wrap: test", err.Error())
+	assert.Equal(t, TerminalErrorReason, GetConditionReasonFromError(err))
+
+	// When controller-runtime terminal error
+	assert.True(t, IsTerminalError(reconcile.TerminalError(errors.New("test"))))
+
+	// When transient error
+	err = errors.Wrap(NewTransientError(errors.New("test"), 10*time.Second), "wrap")
+	retryAfter, ok := GetRetryAfter(err)
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Second, retryAfter)
+	assert.Equal(t, FailedReason, GetConditionReasonFromError(err))
+
+	// When transient error without delay
+	_, ok = GetRetryAfter(NewTransientError(errors.New("test"), 0))
+	assert.False(t, ok)
+
+	// When dependency not ready
+	err = errors.Wrap(NewDependencyNotReadyError(errors.New("test"), 0), "wrap")
+	assert.True(t, IsDependencyNotReadyError(err))
+	retryAfter, ok = GetRetryAfter(err)
+	assert.True(t, ok)
+	assert.Equal(t, DefaultDependencyRetryAfter, retryAfter)
+	assert.Equal(t, DependencyNotReadyReason, GetConditionReasonFromError(err))
+}
+
+func TestResultFromError(t *testing.T) {
+	// When no error
+	res, err := ResultFromError(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+
+	// When standard error
+	res, err = ResultFromError(errors.New("test"))
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, reconcile.TerminalError(nil)))
+	assert.Equal(t, ctrl.Result{}, res)
+
+	// When terminal error
+	res, err = ResultFromError(NewTerminalError(errors.New("test")))
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, reconcile.TerminalError(nil)))
+	assert.Equal(t, ctrl.Result{}, res)
+
+	// When terminal error already converted
+	terminalErr := reconcile.TerminalError(errors.New("test"))
+	_, err = ResultFromError(terminalErr)
+	assert.Equal(t, terminalErr, err)
+
+	// When transient error
+	res, err = ResultFromError(NewTransientError(errors.New("test"), time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: time.Minute}, res)
+
+	// When dependency not ready
+	res, err = ResultFromError(NewDependencyNotReadyError(errors.New("test"), time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: time.Second}, res)
+}
+
+func TestBasicMultiPhaseStepReconcilerActionOnErrorClassification(t *testing.T) {
+	o := &testMultiPhaseObject{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	action := NewBasicMultiPhaseStepReconcilerAction(fake.NewClientBuilder().Build(), "test", "TestReady", recorder)
+	logger := logrus.NewEntry(logrus.New())
+
+	// When dependency not ready, no event
+	res, err := action.OnError(context.Background(), o, nil, NewDependencyNotReadyError(errors.New("test"), time.Second), logger)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: time.Second}, res)
+	assert.Equal(t, DependencyNotReadyReason.String(), GetCondition(o.GetStatus(), "TestReady").Reason)
+	assert.Empty(t, recorder.Events)
+
+	// When terminal error
+	_, err = action.OnError(context.Background(), o, nil, NewTerminalError(errors.New("test")), logger)
+	assert.True(t, errors.Is(err, reconcile.TerminalError(nil)))
+	assert.Equal(t, TerminalErrorReason.String(), GetCondition(o.GetStatus(), "TestReady").Reason)
+	assert.Len(t, recorder.Events, 1)
+}
@@ -0,0 +1,211 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	k8sstrings "k8s.io/utils/strings"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// maxEventMessageLength is the max length of event message accepted by Kubernetes
+	maxEventMessageLength int = 1024
+
+	// maxRecorderEntries is the number of de-duplicate entries or rate limiters before purge the expired ones
+	maxRecorderEntries int = 1024
+)
+
+// EventRecorderOptions permit to configure the EventRecorder
+type EventRecorderOptions struct {
+
+	// DeduplicateWindow is the window where identical events on the same object are emitted only one time
+	// 0 disable the de-duplication
+	DeduplicateWindow time.Duration
+
+	// RateLimit is the number of events per second allowed on the same object
+	// 0 disable the rate limit
+	RateLimit float64
+
+	// RateLimitBurst is the number of events allowed in burst on the same object
+	// Default to 1 when RateLimit is set
+	RateLimitBurst int
+
+	// DisabledReasons is the list of reasons that are never emitted
+	DisabledReasons []string
+}
+
+// EventRecorder is a record.EventRecorder that avoid to spam the API server
+type EventRecorder interface {
+	record.EventRecorder
+
+	// DisableReasons permit to never emit events with this reasons
+	DisableReasons(reasons ...string)
+
+	// EnableReasons permit to emit again events with this reasons
+	EnableReasons(reasons ...string)
+}
+
+// BasicEventRecorder is the basic implementation of EventRecorder interface
+// It wrap a record.EventRecorder to de-duplicate, rate limit and disable events
+type BasicEventRecorder struct {
+	recorder        record.EventRecorder
+	options         EventRecorderOptions
+	mutex           sync.Mutex
+	lastEvents      map[string]time.Time
+	limiters        map[string]*rate.Limiter
+	disabledReasons map[string]struct{}
+	now             func() time.Time
+}
+
+// NewBasicEventRecorder is the basic constructor of EventRecorder interface
+func NewBasicEventRecorder(recorder record.EventRecorder, options EventRecorderOptions) EventRecorder {
+	if recorder == nil {
+		panic("recorder can't be nil")
+	}
+
+	if options.RateLimit > 0 && options.RateLimitBurst <= 0 {
+		options.RateLimitBurst = 1
+	}
+
+	h := &BasicEventRecorder{
+		recorder:        recorder,
+		options:         options,
+		lastEvents:      map[string]time.Time{},
+		limiters:        map[string]*rate.Limiter{},
+		disabledReasons: map[string]struct{}{},
+		now:             time.Now,
+	}
+	h.DisableReasons(options.DisabledReasons...)
+
+	return h
+}
+
+func (h *BasicEventRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if h.shouldEmit(object, eventtype, reason, message) {
+		h.recorder.Event(object, eventtype, reason, message)
+	}
+}
+
+func (h *BasicEventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...any) {
+	if h.shouldEmit(object, eventtype, reason, fmt.Sprintf(messageFmt, args...)) {
+		h.recorder.Eventf(object, eventtype, reason, messageFmt, args...)
+	}
+}
+
+func (h *BasicEventRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...any) {
+	if h.shouldEmit(object, eventtype, reason, fmt.Sprintf(messageFmt, args...)) {
+		h.recorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+	}
+}
+
+func (h *BasicEventRecorder) DisableReasons(reasons ...string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for _, reason := range reasons {
+		h.disabledReasons[reason] = struct{}{}
+	}
+}
+
+func (h *BasicEventRecorder) EnableReasons(reasons ...string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for _, reason := range reasons {
+		delete(h.disabledReasons, reason)
+	}
+}
+
+// shouldEmit permit to know if event must be emitted
+func (h *BasicEventRecorder) shouldEmit(object runtime.Object, eventtype, reason, message string) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if _, isDisabled := h.disabledReasons[reason]; isDisabled {
+		return false
+	}
+
+	objectKey := eventObjectKey(object)
+	now := h.now()
+
+	// De-duplicate identical events
+	if h.options.DeduplicateWindow > 0 {
+		eventKey := strings.Join([]string{objectKey, eventtype, reason, message}, "/")
+		if lastEvent, isFound := h.lastEvents[eventKey]; isFound && now.Sub(lastEvent) < h.options.DeduplicateWindow {
+			return false
+		}
+
+		if len(h.lastEvents) >= maxRecorderEntries {
+			for key, lastEvent := range h.lastEvents {
+				if now.Sub(lastEvent) >= h.options.DeduplicateWindow {
+					delete(h.lastEvents, key)
+				}
+			}
+		}
+		h.lastEvents[eventKey] = now
+	}
+
+	// Rate limit per object
+	if h.options.RateLimit > 0 {
+		limiter, isFound := h.limiters[objectKey]
+		if !isFound {
+			// Limiters with all their tokens are like new ones
+			if len(h.limiters) >= maxRecorderEntries {
+				for key, limiter := range h.limiters {
+					if limiter.TokensAt(now) >= float64(h.options.RateLimitBurst) {
+						delete(h.limiters, key)
+					}
+				}
+			}
+			limiter = rate.NewLimiter(rate.Limit(h.options.RateLimit), h.options.RateLimitBurst)
+			h.limiters[objectKey] = limiter
+		}
+		if !limiter.AllowN(now, 1) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// eventObjectKey permit to compute the key of object that events are attached
+func eventObjectKey(object runtime.Object) string {
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return fmt.Sprintf("%T", object)
+	}
+	if accessor.GetUID() != "" {
+		return string(accessor.GetUID())
+	}
+
+	return fmt.Sprintf("%s/%s/%s", object.GetObjectKind().GroupVersionKind().Kind, accessor.GetNamespace(), accessor.GetName())
+}
+
+// recordObjectsEvent permit to emit one normal event for all objects created, updated or deleted by an action
+func (h *BasicReconcilerAction) recordObjectsEvent(o runtime.Object, reason string, action string, objects []client.Object) {
+	if len(objects) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(objects))
+	for _, object := range objects {
+		names = append(names, fmt.Sprintf("'%s'", object.GetName()))
+	}
+
+	var message string
+	if len(names) == 1 {
+		message = fmt.Sprintf("Object %s successfully %s", names[0], action)
+	} else {
+		message = fmt.Sprintf("Objects %s successfully %s", strings.Join(names, ", "), action)
+	}
+
+	h.Recorder().Event(o, corev1.EventTypeNormal, reason, k8sstrings.ShortenString(message, maxEventMessageLength))
+}
@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBasicEventRecorder(t *testing.T) {
+	o := &testMultiPhaseObject{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+			UID:  "uid",
+		},
+	}
+	currentTime := time.Now()
+	fakeRecorder := record.NewFakeRecorder(100)
+	recorder := NewBasicEventRecorder(fakeRecorder, EventRecorderOptions{
+		DeduplicateWindow: time.Minute,
+		DisabledReasons:   []string{"Disabled"},
+	}).(*BasicEventRecorder)
+	recorder.now = func() time.Time { return currentTime }
+
+	// When same event in window, emit only one time
+	recorder.Event(o, corev1.EventTypeNormal, "Test", "message")
+	recorder.Eventf(o, corev1.EventTypeNormal, "Test", "%s", "message")
+	assert.Len(t, fakeRecorder.Events, 1)
+
+	// When other message
+	recorder.Event(o, corev1.EventTypeNormal, "Test", "other message")
+	assert.Len(t, fakeRecorder.Events, 2)
+
+	// When window is expired
+	currentTime = currentTime.Add(2 * time.Minute)
+	recorder.Event(o, corev1.EventTypeNormal, "Test", "message")
+	assert.Len(t, fakeRecorder.Events, 3)
+
+	// When reason is disabled
+	recorder.Event(o, corev1.EventTypeNormal, "Disabled", "message")
+	assert.Len(t, fakeRecorder.Events, 3)
+
+	// When reason is enabled again
+	recorder.EnableReasons("Disabled")
+	recorder.Event(o, corev1.EventTypeNormal, "Disabled", "message")
+	assert.Len(t, fakeRecorder.Events, 4)
+}
+
+func TestBasicEventRecorderRateLimit(t *testing.T) {
+	o := &testMultiPhaseObject{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+	}
+	o2 := &testMultiPhaseObject{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test2",
+		},
+	}
+	currentTime := time.Now()
+	fakeRecorder := record.NewFakeRecorder(100)
+	recorder := NewBasicEventRecorder(fakeRecorder, EventRecorderOptions{
+		RateLimit: 1,
+	}).(*BasicEventRecorder)
+	recorder.now = func() time.Time { return currentTime }
+
+	// When burst is reached
+	recorder.Event(o, corev1.EventTypeNormal, "Test", "message 1")
+	recorder.Event(o, corev1.EventTypeNormal, "Test", "message 2")
+	assert.Len(t, fakeRecorder.Events, 1)
+
+	// When other object
+	recorder.Event(o2, corev1.EventTypeNormal, "Test", "message 1")
+	assert.Len(t, fakeRecorder.Events, 2)
+
+	// When token is available again
+	currentTime = currentTime.Add(time.Second)
+	recorder.Event(o, corev1.EventTypeNormal, "Test", "message 3")
+	assert.Len(t, fakeRecorder.Events, 3)
+
+	// When too many limiters, the idle ones are purged
+	for i := 0; i < maxRecorderEntries; i++ {
+		recorder.Event(&testMultiPhaseObject{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("test-%d", i)}}, corev1.EventTypeNormal, "Test", "message")
+		<-fakeRecorder.Events
+	}
+	assert.Greater(t, len(recorder.limiters), maxRecorderEntries)
+	currentTime = currentTime.Add(time.Second)
+	recorder.Event(&testMultiPhaseObject{ObjectMeta: metav1.ObjectMeta{Name: "test3"}}, corev1.EventTypeNormal, "Test", "message 1")
+	assert.Len(t, recorder.limiters, 1)
+}
+
+func TestBasicMultiPhaseStepReconcilerActionAggregateEvents(t *testing.T) {
+	o := &testMultiPhaseObject{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	fakeClient := fake.NewClientBuilder().WithObjects(
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm1", Namespace: "default"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm2", Namespace: "default"}},
+	).Build()
+	action := NewBasicMultiPhaseStepReconcilerAction(fakeClient, "test", "TestReady", recorder)
+	logger := logrus.NewEntry(logrus.New())
+
+	objects := []client.Object{
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm1", Namespace: "default"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm2", Namespace: "default"}},
+	}
+
+	// When update multiple objects, only one event
+	_, err := action.Update(context.Background(), o, nil, objects, logger)
+	assert.NoError(t, err)
+	if assert.Len(t, recorder.Events, 1) {
+		assert.Equal(t, "Normal UpdateCompleted Objects 'cm1', 'cm2' successfully updated", <-recorder.Events)
+	}
+
+	// When partial error, only objects processed are reported
+	objects = []client.Object{
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm1", Namespace: "default"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm3", Namespace: "default"}},
+	}
+	_, err = action.Delete(context.Background(), o, nil, objects, logger)
+	assert.Error(t, err)
+	if assert.Len(t, recorder.Events, 1) {
+		assert.Equal(t, "Normal DeleteCompleted Object 'cm1' successfully deleted", <-recorder.Events)
+	}
+
+	// When nothing to do, no event
+	_, err = action.Create(context.Background(), o, nil, nil, logger)
+	assert.NoError(t, err)
+	assert.Empty(t, recorder.Events)
+}
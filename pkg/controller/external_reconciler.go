@@ -130,8 +130,13 @@ func (h *StdReconciler) Reconcile(ctx context.Context, req ctrl.Request, r clien
 	}
 
 	// Handle status update if exist
-	if getObjectStatus(r) != nil {
-		currentStatus, err := copystructure.Copy(getObjectStatus(r))
+	status, err := getObjectStatus(r)
+	if err != nil {
+		h.log.Errorf("Error when get object status: %s", err.Error())
+		return res, err
+	}
+	if status != nil {
+		currentStatus, err := copystructure.Copy(status)
 		if err != nil {
 			return res, err
 		}
@@ -139,7 +144,8 @@ func (h *StdReconciler) Reconcile(ctx context.Context, req ctrl.Request, r clien
 			if err != nil {
 				h.reconciler.OnError(ctx, r, data, meta, err)
 			}
-			if !reflect.DeepEqual(currentStatus, getObjectStatus(r)) {
+			newStatus, _ := getObjectStatus(r)
+			if !reflect.DeepEqual(currentStatus, newStatus) {
 				h.log.Debug("Detect that it need to update status")
 				if err = h.Client.Status().Update(ctx, r); err != nil {
 					h.log.Errorf("Error when update resource status: %s", err.Error())
@@ -169,7 +175,7 @@ func (h *StdReconciler) Reconcile(ctx context.Context, req ctrl.Request, r clien
 	h.log.Debug("Get resource successfully")
 
 	// Check if resource need to be deleted
-	if !getObjectMeta(r).DeletionTimestamp.IsZero() {
+	if !r.GetDeletionTimestamp().IsZero() {
 		if h.finalizer != "" && controllerutil.ContainsFinalizer(r, h.finalizer) {
 			h.log.Info("Start delete step")
 			if err = h.reconciler.Delete(ctx, r, data, meta); err != nil {
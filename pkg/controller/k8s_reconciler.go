@@ -79,10 +79,11 @@ type K8sDiff struct {
 
 type StdK8sReconciler struct {
 	client.Client
-	finalizer  string
-	reconciler K8sReconciler
-	log        *logrus.Entry
-	recorder   record.EventRecorder
+	finalizer      string
+	reconciler     K8sReconciler
+	log            *logrus.Entry
+	recorder       record.EventRecorder
+	reconcileDelay time.Duration
 }
 
 func NewStdK8sReconciler(client client.Client, finalizer string, reconciler K8sReconciler, logger *logrus.Entry, recorder record.EventRecorder) (stdK8sReconciler *StdK8sReconciler, err error) {
@@ -92,11 +93,12 @@ func NewStdK8sReconciler(client client.Client, finalizer string, reconciler K8sR
 	}
 
 	stdK8sReconciler = &StdK8sReconciler{
-		Client:     client,
-		finalizer:  finalizer,
-		reconciler: reconciler,
-		recorder:   recorder,
-		log:        logger,
+		Client:         client,
+		finalizer:      finalizer,
+		reconciler:     reconciler,
+		recorder:       recorder,
+		log:            logger,
+		reconcileDelay: DefaultReconcileDelay,
 	}
 
 	if stdK8sReconciler.log == nil {
@@ -106,6 +108,11 @@ func NewStdK8sReconciler(client client.Client, finalizer string, reconciler K8sR
 	return stdK8sReconciler, nil
 }
 
+// SetReconcileDelay permit to set the delay waited at the beginning of each reconcile loop
+func (h *StdK8sReconciler) SetReconcileDelay(delay time.Duration) {
+	h.reconcileDelay = delay
+}
+
 // ReconcileK8sResources permit to reconcile kubernetes resources, so the step is not the same on Reconcile.
 // When handle kubernetes resources, you should to chain the reconcile on multiple resources
 // It will run on following steps
@@ -129,7 +136,7 @@ func (h *StdK8sReconciler) Reconcile(ctx context.Context, req ctrl.Request, r cl
 	defer h.log.Info("Finish reconcile loop")
 
 	// Wait few second to be sure status is propaged througout ETCD
-	time.Sleep(time.Second * 1)
+	time.Sleep(h.reconcileDelay)
 
 	// Get current resource
 	if err = h.Get(ctx, req.NamespacedName, r); err != nil {
@@ -155,13 +162,19 @@ func (h *StdK8sReconciler) Reconcile(ctx context.Context, req ctrl.Request, r cl
 	}
 
 	// Handle status update if exist
-	if getObjectStatus(r) != nil {
-		currentStatus, err := copystructure.Copy(getObjectStatus(r))
+	status, err := getObjectStatus(r)
+	if err != nil {
+		h.log.Errorf("Error when get object status: %s", err.Error())
+		return res, err
+	}
+	if status != nil {
+		currentStatus, err := copystructure.Copy(status)
 		if err != nil {
 			return res, err
 		}
 		defer func() {
-			if !reflect.DeepEqual(currentStatus, getObjectStatus(r)) {
+			newStatus, _ := getObjectStatus(r)
+			if !reflect.DeepEqual(currentStatus, newStatus) {
 				h.log.Debug("Detect that it need to update status")
 				if err = h.Client.Status().Update(ctx, r); err != nil {
 					h.log.Errorf("Error when update resource status: %s", err.Error())
@@ -194,7 +207,7 @@ func (h *StdK8sReconciler) Reconcile(ctx context.Context, req ctrl.Request, r cl
 	h.log.Debug("Read parent reconciler successfully")
 
 	// Handle delete finalizer
-	if !getObjectMeta(r).DeletionTimestamp.IsZero() {
+	if !r.GetDeletionTimestamp().IsZero() {
 		if h.finalizer != "" && controllerutil.ContainsFinalizer(r, h.finalizer) {
 			if err = h.reconciler.Delete(ctx, r, data); err != nil {
 				h.log.Errorf("Error when delete resource: %s", err.Error())
@@ -0,0 +1,251 @@
+package controller
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// cronField is the definition of one field of cron expression
+type cronField struct {
+	name string
+	min  int
+	max  int
+}
+
+var (
+	cronMinute     = cronField{name: "minute", min: 0, max: 59}
+	cronHour       = cronField{name: "hour", min: 0, max: 23}
+	cronDayOfMonth = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth      = cronField{name: "month", min: 1, max: 12}
+	cronDayOfWeek  = cronField{name: "day of week", min: 0, max: 7}
+)
+
+// cronSchedule is a parsed standard cron expression with 5 fields
+// Each field is stored as bitset of allowed values
+type cronSchedule struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+
+	// Standard cron use OR between day of month and day of week when both are restricted
+	dayOfMonthStar bool
+	dayOfWeekStar  bool
+}
+
+// MaintenanceWindow is a recurring window where disruptive actions are allowed
+// The window start on each cron schedule (evaluated in UTC) and stay open during the duration
+type MaintenanceWindow struct {
+	schedule cronSchedule
+	duration time.Duration
+}
+
+// ParseMaintenanceWindow permit to parse maintenance window like "0 2 * * 6 4h"
+// The 5 first fields are standard cron expression (minute hour dayOfMonth month dayOfWeek) and the last is the duration
+func ParseMaintenanceWindow(window string) (maintenanceWindow *MaintenanceWindow, err error) {
+	fields := strings.Fields(window)
+	if len(fields) != 6 {
+		return nil, errors.Errorf("Maintenance window '%s' must have 5 cron fields and one duration", window)
+	}
+
+	schedule, err := parseCronSchedule(fields[0:5])
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error when parse cron of maintenance window '%s'", window)
+	}
+
+	duration, err := time.ParseDuration(fields[5])
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error when parse duration of maintenance window '%s'", window)
+	}
+	if duration <= 0 {
+		return nil, errors.Errorf("Duration of maintenance window '%s' must be positive", window)
+	}
+
+	return &MaintenanceWindow{
+		schedule: *schedule,
+		duration: duration,
+	}, nil
+}
+
+// ParseMaintenanceWindows permit to parse list of maintenance windows separated by ';'
+func ParseMaintenanceWindows(windows string) (maintenanceWindows []*MaintenanceWindow, err error) {
+	maintenanceWindows = make([]*MaintenanceWindow, 0)
+	for _, window := range strings.Split(windows, ";") {
+		if strings.TrimSpace(window) == "" {
+			continue
+		}
+		maintenanceWindow, err := ParseMaintenanceWindow(window)
+		if err != nil {
+			return nil, err
+		}
+		maintenanceWindows = append(maintenanceWindows, maintenanceWindow)
+	}
+
+	return maintenanceWindows, nil
+}
+
+// Contains permit to know if the time is inside the maintenance window
+func (h *MaintenanceWindow) Contains(t time.Time) bool {
+	t = t.UTC().Truncate(time.Minute)
+
+	// Search the last schedule that can cover the time
+	return !h.schedule.previous(t, t.Add(-h.duration)).IsZero()
+}
+
+// Next permit to get the next start of maintenance window after the time
+// It return zero time if no schedule is found in the next 5 years
+func (h *MaintenanceWindow) Next(t time.Time) time.Time {
+	return h.schedule.next(t)
+}
+
+// parseCronSchedule permit to parse the 5 fields of cron expression
+func parseCronSchedule(fields []string) (schedule *cronSchedule, err error) {
+	schedule = &cronSchedule{}
+
+	if schedule.minute, err = parseCronField(fields[0], cronMinute); err != nil {
+		return nil, err
+	}
+	if schedule.hour, err = parseCronField(fields[1], cronHour); err != nil {
+		return nil, err
+	}
+	if schedule.dayOfMonth, err = parseCronField(fields[2], cronDayOfMonth); err != nil {
+		return nil, err
+	}
+	if schedule.month, err = parseCronField(fields[3], cronMonth); err != nil {
+		return nil, err
+	}
+	if schedule.dayOfWeek, err = parseCronField(fields[4], cronDayOfWeek); err != nil {
+		return nil, err
+	}
+
+	// Sunday can be 0 or 7
+	if schedule.dayOfWeek&(1<<7) > 0 {
+		schedule.dayOfWeek |= 1
+	}
+
+	// Like standard cron, '*/n' is also star for day fields
+	schedule.dayOfMonthStar = strings.HasPrefix(fields[2], "*")
+	schedule.dayOfWeekStar = strings.HasPrefix(fields[4], "*")
+
+	return schedule, nil
+}
+
+// parseCronField permit to parse one cron field. It support '*', lists, ranges and steps
+func parseCronField(expr string, field cronField) (bits uint64, err error) {
+	for _, part := range strings.Split(expr, ",") {
+		var (
+			start, end int
+			step       = 1
+		)
+
+		rangeExpr, stepExpr, hasStep := strings.Cut(part, "/")
+		if hasStep {
+			if step, err = strconv.Atoi(stepExpr); err != nil || step <= 0 {
+				return 0, errors.Errorf("Invalid step '%s' for %s", stepExpr, field.name)
+			}
+		}
+
+		switch {
+		case rangeExpr == "*":
+			start, end = field.min, field.max
+		case strings.Contains(rangeExpr, "-"):
+			startExpr, endExpr, _ := strings.Cut(rangeExpr, "-")
+			if start, err = strconv.Atoi(startExpr); err != nil {
+				return 0, errors.Errorf("Invalid value '%s' for %s", startExpr, field.name)
+			}
+			if end, err = strconv.Atoi(endExpr); err != nil {
+				return 0, errors.Errorf("Invalid value '%s' for %s", endExpr, field.name)
+			}
+		default:
+			if start, err = strconv.Atoi(rangeExpr); err != nil {
+				return 0, errors.Errorf("Invalid value '%s' for %s", rangeExpr, field.name)
+			}
+			end = start
+			if hasStep {
+				end = field.max
+			}
+		}
+
+		if start < field.min || end > field.max || start > end {
+			return 0, errors.Errorf("Value '%s' out of range [%d-%d] for %s", part, field.min, field.max, field.name)
+		}
+
+		for i := start; i <= end; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+
+	return bits, nil
+}
+
+// dayMatches permit to know if the day match the schedule
+func (h *cronSchedule) dayMatches(t time.Time) bool {
+	dayOfMonthMatch := h.dayOfMonth&(1<<uint(t.Day())) > 0
+	dayOfWeekMatch := h.dayOfWeek&(1<<uint(t.Weekday())) > 0
+
+	if h.dayOfMonthStar || h.dayOfWeekStar {
+		return dayOfMonthMatch && dayOfWeekMatch
+	}
+	return dayOfMonthMatch || dayOfWeekMatch
+}
+
+// next permit to get the next time strictly after t that match the schedule
+// It skip the whole month, day or hour when they not match to stay fast
+func (h *cronSchedule) next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if h.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !h.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if h.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, time.UTC)
+			continue
+		}
+		if h.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// previous permit to get the last time before or equal to t that match the schedule
+// It return zero time if no time match after the limit
+func (h *cronSchedule) previous(t time.Time, limit time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute)
+
+	for t.After(limit) {
+		if h.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC).Add(-time.Minute)
+			continue
+		}
+		if !h.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Add(-time.Minute)
+			continue
+		}
+		if h.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, time.UTC).Add(-time.Minute)
+			continue
+		}
+		if h.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(-time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMaintenanceWindow(t *testing.T) {
+	// When valid window
+	_, err := ParseMaintenanceWindow("0 2 * * 6 4h")
+	assert.NoError(t, err)
+
+	// When lists, ranges and steps
+	_, err = ParseMaintenanceWindow("0,30 */2 1-15 1-12/3 1-5 30m")
+	assert.NoError(t, err)
+
+	// When bad number of fields
+	_, err = ParseMaintenanceWindow("0 2 * * 4h")
+	assert.Error(t, err)
+
+	// When value out of range
+	_, err = ParseMaintenanceWindow("60 2 * * * 4h")
+	assert.Error(t, err)
+
+	// When bad step
+	_, err = ParseMaintenanceWindow("*/0 2 * * * 4h")
+	assert.Error(t, err)
+
+	// When bad duration
+	_, err = ParseMaintenanceWindow("0 2 * * * 4")
+	assert.Error(t, err)
+
+	// When multiple windows
+	windows, err := ParseMaintenanceWindows("0 2 * * 6 4h; 0 22 * * 1-5 1h;")
+	assert.NoError(t, err)
+	assert.Len(t, windows, 2)
+
+	_, err = ParseMaintenanceWindows("0 2 * * 6 4h;bad")
+	assert.Error(t, err)
+}
+
+func TestMaintenanceWindowContains(t *testing.T) {
+	// Saturday at 2h during 4h
+	window, err := ParseMaintenanceWindow("0 2 * * 6 4h")
+	assert.NoError(t, err)
+
+	// 2024-01-06 is saturday
+	assert.True(t, window.Contains(time.Date(2024, 1, 6, 2, 0, 0, 0, time.UTC)))
+	assert.True(t, window.Contains(time.Date(2024, 1, 6, 5, 59, 0, 0, time.UTC)))
+	assert.False(t, window.Contains(time.Date(2024, 1, 6, 6, 0, 0, 0, time.UTC)))
+	assert.False(t, window.Contains(time.Date(2024, 1, 6, 1, 59, 0, 0, time.UTC)))
+	assert.False(t, window.Contains(time.Date(2024, 1, 7, 3, 0, 0, 0, time.UTC)))
+
+	// When window overlap the next day
+	window, err = ParseMaintenanceWindow("0 22 * * 6 4h")
+	assert.NoError(t, err)
+	assert.True(t, window.Contains(time.Date(2024, 1, 7, 1, 0, 0, 0, time.UTC)))
+
+	// When sunday is 7
+	window, err = ParseMaintenanceWindow("0 0 * * 7 1h")
+	assert.NoError(t, err)
+	assert.True(t, window.Contains(time.Date(2024, 1, 7, 0, 30, 0, 0, time.UTC)))
+
+	// When day of month and day of week are restricted, one of them must match
+	window, err = ParseMaintenanceWindow("0 0 1 * 1 1h")
+	assert.NoError(t, err)
+	assert.True(t, window.Contains(time.Date(2024, 2, 1, 0, 30, 0, 0, time.UTC)))
+	assert.True(t, window.Contains(time.Date(2024, 1, 8, 0, 30, 0, 0, time.UTC)))
+	assert.False(t, window.Contains(time.Date(2024, 1, 9, 0, 30, 0, 0, time.UTC)))
+
+	// When step on day of week, it is star so both must match
+	window, err = ParseMaintenanceWindow("0 0 1 * */2 1h")
+	assert.NoError(t, err)
+	assert.False(t, window.Contains(time.Date(2024, 1, 2, 0, 30, 0, 0, time.UTC)))
+	assert.False(t, window.Contains(time.Date(2024, 1, 1, 0, 30, 0, 0, time.UTC)))
+	assert.True(t, window.Contains(time.Date(2024, 2, 1, 0, 30, 0, 0, time.UTC)))
+
+	// When window is very long
+	window, err = ParseMaintenanceWindow("0 0 1 1 * 8760h")
+	assert.NoError(t, err)
+	assert.True(t, window.Contains(time.Date(2023, 12, 31, 23, 59, 0, 0, time.UTC)))
+	assert.True(t, window.Contains(time.Date(2024, 12, 30, 23, 59, 0, 0, time.UTC)))
+	assert.False(t, window.Contains(time.Date(2024, 12, 31, 0, 30, 0, 0, time.UTC)))
+}
+
+func TestMaintenanceWindowNext(t *testing.T) {
+	window, err := ParseMaintenanceWindow("0 2 * * 6 4h")
+	assert.NoError(t, err)
+
+	// When before the window
+	assert.Equal(t, time.Date(2024, 1, 6, 2, 0, 0, 0, time.UTC), window.Next(time.Date(2024, 1, 2, 10, 15, 30, 0, time.UTC)))
+
+	// When inside the window, get the next one
+	assert.Equal(t, time.Date(2024, 1, 13, 2, 0, 0, 0, time.UTC), window.Next(time.Date(2024, 1, 6, 2, 0, 0, 0, time.UTC)))
+
+	// When next year
+	window, err = ParseMaintenanceWindow("*/15 3 29 2 * 1h")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2028, 2, 29, 3, 0, 0, 0, time.UTC), window.Next(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)))
+}
@@ -2,12 +2,12 @@ package controller
 
 import (
 	"context"
+	"time"
 
 	"emperror.dev/errors"
 	"github.com/disaster37/operator-sdk-extra/pkg/apis/shared"
 	"github.com/disaster37/operator-sdk-extra/pkg/object"
 	"github.com/sirupsen/logrus"
-	condition "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/strings"
@@ -15,6 +15,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// ConditionNotReadyRetryAfter is the delay to requeue when the condition computed from the sub conditions is not ready
+var ConditionNotReadyRetryAfter = 30 * time.Second
+
 // MultiPhaseReconcilerAction is the methode needed by step reconciler to reconcile your custom resource
 type MultiPhaseReconcilerAction interface {
 	BaseReconciler
@@ -40,29 +43,30 @@ type MultiPhaseReconcilerAction interface {
 // BasicMultiPhaseReconcilerAction is the basic implementation of MultiPhaseReconcilerAction interface
 type BasicMultiPhaseReconcilerAction struct {
 	BasicReconcilerAction
+	aggregator ConditionAggregator
 }
 
 // NewBasicMultiPhaseReconcilerAction is the basic contructor of MultiPhaseReconcilerAction interface
-func NewBasicMultiPhaseReconcilerAction(client client.Client, conditionName shared.ConditionName, recorder record.EventRecorder) (multiPhaseReconciler MultiPhaseReconcilerAction) {
-	return &BasicMultiPhaseReconcilerAction{
+// When subConditionNames are provided (usually the condition name of each step), the condition is computed from them on success.
+// Else the condition is set to true on success.
+func NewBasicMultiPhaseReconcilerAction(client client.Client, conditionName shared.ConditionName, recorder record.EventRecorder, subConditionNames ...shared.ConditionName) (multiPhaseReconciler MultiPhaseReconcilerAction) {
+	action := &BasicMultiPhaseReconcilerAction{
 		BasicReconcilerAction: BasicReconcilerAction{
 			BaseReconciler: NewBaseReconciler(client, recorder),
 			conditionName:  conditionName,
 		},
 	}
+
+	if len(subConditionNames) > 0 {
+		action.aggregator = NewBasicConditionAggregator(conditionName, subConditionNames...)
+	}
+
+	return action
 }
 
 func (h *BasicMultiPhaseReconcilerAction) Configure(ctx context.Context, req ctrl.Request, o object.MultiPhaseObject, data map[string]any, logger *logrus.Entry) (res ctrl.Result, err error) {
 
-	conditions := o.GetStatus().GetConditions()
-	if condition.FindStatusCondition(conditions, h.conditionName.String()) == nil {
-		condition.SetStatusCondition(&conditions, metav1.Condition{
-			Type:   h.conditionName.String(),
-			Status: metav1.ConditionFalse,
-			Reason: "Initialize",
-		})
-	}
-	o.GetStatus().SetConditions(conditions)
+	NewBasicConditionManager(o, o.GetStatus()).Init(h.conditionName)
 
 	return res, nil
 }
@@ -77,32 +81,31 @@ func (h *BasicMultiPhaseReconcilerAction) Delete(ctx context.Context, o object.M
 
 func (h *BasicMultiPhaseReconcilerAction) OnError(ctx context.Context, o object.MultiPhaseObject, data map[string]any, currentErr error, logger *logrus.Entry) (res ctrl.Result, err error) {
 
-	o.GetStatus().SetIsOnError(true)
-	o.GetStatus().SetLastErrorMessage(strings.ShortenString(currentErr.Error(), ShortenError))
+	// Dependency not ready is not an error, we only wait it
+	if !IsDependencyNotReadyError(currentErr) {
+		o.GetStatus().SetIsOnError(true)
+		o.GetStatus().SetLastErrorMessage(strings.ShortenString(currentErr.Error(), ShortenError))
+	}
 
-	conditions := o.GetStatus().GetConditions()
-	condition.SetStatusCondition(&conditions, metav1.Condition{
-		Type:    h.conditionName.String(),
-		Status:  metav1.ConditionFalse,
-		Reason:  "Failed",
-		Message: strings.ShortenString(currentErr.Error(), ShortenError),
-	})
-	o.GetStatus().SetConditions(conditions)
+	NewBasicConditionManager(o, o.GetStatus()).MarkFalse(h.conditionName, GetConditionReasonFromError(currentErr), "%s", strings.ShortenString(currentErr.Error(), ShortenError))
 
-	return res, errors.Wrap(currentErr, "Error on reconciler")
+	return ResultFromError(errors.Wrap(currentErr, "Error on reconciler"))
 }
 
 func (h *BasicMultiPhaseReconcilerAction) OnSuccess(ctx context.Context, o object.MultiPhaseObject, data map[string]any, logger *logrus.Entry) (res ctrl.Result, err error) {
 
-	conditions := o.GetStatus().GetConditions()
-	if !condition.IsStatusConditionPresentAndEqual(conditions, h.conditionName.String(), metav1.ConditionTrue) {
-		condition.SetStatusCondition(&conditions, metav1.Condition{
-			Type:   h.conditionName.String(),
-			Status: metav1.ConditionTrue,
-			Reason: "Ready",
-		})
+	if h.aggregator != nil {
+		aggregatedCondition := h.aggregator.Aggregate(o.GetStatus(), o.GetGeneration())
+		if aggregatedCondition.Status != metav1.ConditionTrue {
+			// The phase is kept until all sub conditions are ready
+			logger.Debugf("Condition %s is not ready: %s", h.conditionName.String(), aggregatedCondition.Message)
+			o.GetStatus().SetObservedGeneration(o.GetGeneration())
+
+			return ctrl.Result{RequeueAfter: ConditionNotReadyRetryAfter}, nil
+		}
+	} else {
+		NewBasicConditionManager(o, o.GetStatus()).MarkTrue(h.conditionName, ReadyReason, "")
 	}
-	o.GetStatus().SetConditions(conditions)
 
 	o.GetStatus().SetPhaseName(RunningPhase)
 	o.GetStatus().SetIsOnError(false)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"emperror.dev/errors"
@@ -24,6 +25,12 @@ type MultiPhaseReconciler interface {
 
 	// Reconcile permit to orchestrate all phase needed to successfully reconcile the object
 	Reconcile(ctx context.Context, req ctrl.Request, o object.MultiPhaseObject, data map[string]interface{}, reconcilerAction MultiPhaseReconcilerAction, reconcilersStepAction ...MultiPhaseStepReconcilerAction) (res ctrl.Result, err error)
+
+	// SetReconcileDelay permit to set the delay waited at the beginning of each reconcile loop
+	SetReconcileDelay(delay time.Duration)
+
+	// DependencyRegistry permit to get the registry of dependencies read during reconcile, to watch them with SetupTrackedDependenciesWithManager
+	DependencyRegistry() *DependencyRegistry
 }
 
 // BasicMultiPhaseReconciler is the basic multi phase reconsiler you can used whe  you should to create multiple k8s resources
@@ -58,12 +65,20 @@ func (h *BasicMultiPhaseReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	logger.Infof("Starting reconcile loop")
 	defer logger.Info("Finish reconcile loop")
 
+	// Handle terminal, transient and dependency errors when OnError not already do it
+	defer func() {
+		if err != nil {
+			res, err = ResultFromError(err)
+		}
+	}()
+
 	// Wait few second to be sure status is propaged througout ETCD
-	time.Sleep(time.Second * 1)
+	time.Sleep(h.reconcileDelay)
 
 	// Get current resource
 	if err = h.Client().Get(ctx, req.NamespacedName, o); err != nil {
 		if k8serrors.IsNotFound(err) {
+			untrackDependencies(h.Client(), h.dependencyRegistry, o, req.NamespacedName)
 			return res, nil
 		}
 		logger.Errorf("Error when get object: %s", err.Error())
@@ -85,15 +100,21 @@ func (h *BasicMultiPhaseReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 
 	// Handle status update if exist
-	if getObjectStatus(o) != nil {
-		currentStatus, err := copystructure.Copy(getObjectStatus(o))
+	status, err := getObjectStatus(o)
+	if err != nil {
+		logger.Errorf("Error when get object status: %s", err.Error())
+		return res, errors.Wrap(err, ErrWhenGetObjectStatus.Error())
+	}
+	if status != nil {
+		currentStatus, err := copystructure.Copy(status)
 		if err != nil {
 			logger.Errorf("Error when get object status: %s", err.Error())
 			return res, errors.Wrap(err, ErrWhenGetObjectStatus.Error())
 		}
 		defer func() {
-			if !reflect.DeepEqual(currentStatus, getObjectStatus(o)) {
-				logger.Debugf("Detect that it need to update status with diff:\n%s", cmp.Diff(currentStatus, getObjectStatus(o)))
+			newStatus, _ := getObjectStatus(o)
+			if !reflect.DeepEqual(currentStatus, newStatus) {
+				logger.Debugf("Detect that it need to update status with diff:\n%s", cmp.Diff(currentStatus, newStatus))
 				if err = h.Client().Status().Update(ctx, o); err != nil {
 					logger.Errorf("Error when update resource status: %s", err.Error())
 				}
@@ -108,6 +129,18 @@ func (h *BasicMultiPhaseReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return res, nil
 	}
 
+	// Record the Secrets and ConfigMaps read during reconcile, to reconcile again when they change
+	ctx, registerDependencies := trackDependencies(ctx, h.Client(), h.dependencyRegistry, o, logger)
+	defer registerDependencies()
+
+	// Compute actions allowed by pause and maintenance window annotations
+	control, err := NewReconcileControl(o, time.Now())
+	if err != nil {
+		logger.Errorf("Error when compute reconcile control: %s", err.Error())
+		return reconcilerAction.OnError(ctx, o, data, NewTerminalError(errors.Wrap(err, ErrWhenComputeReconcileControl.Error())), logger)
+	}
+	ctx = WithReconcileControl(ctx, control)
+
 	// Configure to optional get driver client (call meta)
 	res, err = reconcilerAction.Configure(ctx, req, o, data, logger)
 	if err != nil {
@@ -131,7 +164,7 @@ func (h *BasicMultiPhaseReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 
 	// Handle delete finalizer
-	if !getObjectMeta(o).DeletionTimestamp.IsZero() {
+	if !o.GetDeletionTimestamp().IsZero() {
 		if h.finalizer.String() != "" && controllerutil.ContainsFinalizer(o, h.finalizer.String()) {
 			if err = reconcilerAction.Delete(ctx, o, data, logger); err != nil {
 				logger.Errorf("Error when call 'delete' from reconciler: %s", err.Error())
@@ -139,6 +172,12 @@ func (h *BasicMultiPhaseReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 			}
 			logger.Debug("Delete successfully")
 
+			if err = h.deleteTrackedChildren(ctx, o, logger, reconcilersStepAction...); err != nil {
+				logger.Errorf("Error when delete tracked children: %s", err.Error())
+				return reconcilerAction.OnError(ctx, o, data, errors.Wrap(err, ErrWhenDeleteTrackedChildren.Error()), logger)
+			}
+			logger.Debug("Delete tracked children successfully")
+
 			controllerutil.RemoveFinalizer(o, h.finalizer.String())
 			if err = h.Client().Update(ctx, o); err != nil {
 				logger.Errorf("Failed to remove finalizer: %s", err.Error())
@@ -163,9 +202,21 @@ func (h *BasicMultiPhaseReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 			return res, nil
 		}
 
+		// The next steps can depend on objects not yet updated by this step
+		if control.IsBlocked() {
+			break
+		}
+
 		time.Sleep(time.Millisecond * 1)
 	}
 
+	// Some actions are skipped by pause or maintenance window, wait to run them
+	control.SetCondition(NewBasicConditionManager(o, o.GetStatus()))
+	if control.IsBlocked() {
+		logger.Infof("Skip actions until pause or maintenance window allow them: %s", strings.Join(control.GetBlockedActions(), ", "))
+		return ctrl.Result{RequeueAfter: control.RequeueAfter()}, nil
+	}
+
 	res, err = reconcilerAction.OnSuccess(ctx, o, data, logger)
 	if err != nil {
 		logger.Errorf("Error when call 'onSuccess' from reconciler: %s", err.Error())
@@ -175,3 +226,31 @@ func (h *BasicMultiPhaseReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 
 	return res, nil
 }
+
+// deleteTrackedChildren permit to delete the children linked with tracking labels of steps
+// The children are deleted on the target cluster of step if any
+func (h *BasicMultiPhaseReconciler) deleteTrackedChildren(ctx context.Context, o object.MultiPhaseObject, logger *logrus.Entry, reconcilersStepAction ...MultiPhaseStepReconcilerAction) (err error) {
+	for _, reconciler := range reconcilersStepAction {
+		lists := reconciler.GetTrackedChildrenLists()
+		if len(lists) == 0 {
+			continue
+		}
+
+		targetClient := h.Client()
+		if targetAction, ok := reconciler.(TargetClusterReconcilerAction); ok {
+			c, err := targetAction.GetTargetClient(ctx, o, logger)
+			if err != nil {
+				return errors.Wrapf(err, "Error when get target client of step %s", reconciler.GetPhaseName().String())
+			}
+			if c != nil {
+				targetClient = c
+			}
+		}
+
+		if err = DeleteTrackedChildren(ctx, targetClient, o, lists...); err != nil {
+			return errors.Wrapf(err, "Error when delete tracked children of step %s", reconciler.GetPhaseName().String())
+		}
+	}
+
+	return nil
+}
@@ -11,17 +11,17 @@ import (
 	"github.com/disaster37/operator-sdk-extra/pkg/object"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
-	condition "k8s.io/apimachinery/pkg/api/meta"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	k8sstrings "k8s.io/utils/strings"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
 
 // MultiPhaseStepReconcilerAction is the interface that use by reconciler step to reconcile your intermediate K8s resources
 type MultiPhaseStepReconcilerAction interface {
 	BaseReconciler
+	TrackedChildrenReconcilerAction
 
 	// Configure permit to init condition on status
 	Configure(ctx context.Context, req ctrl.Request, o object.MultiPhaseObject, logger *logrus.Entry) (res ctrl.Result, err error)
@@ -56,9 +56,12 @@ type MultiPhaseStepReconcilerAction interface {
 }
 
 // BasicMultiPhaseStepReconcilerAction is the basic implementation of MultiPhaseStepReconcilerAction
+// It get the tracked children lists from SetTrackedChildrenLists
 type BasicMultiPhaseStepReconcilerAction struct {
 	BasicReconcilerAction
-	phaseName shared.PhaseName
+	phaseName            shared.PhaseName
+	ownershipStrategy    OwnershipStrategy
+	trackedChildrenLists []client.ObjectList
 }
 
 // NewBasicMultiPhaseStepReconcilerAction is the basic constructor of MultiPhaseStepReconcilerAction interface
@@ -70,25 +73,78 @@ func NewBasicMultiPhaseStepReconcilerAction(client client.Client, phaseName shar
 			recorder,
 			conditionName,
 		),
-		phaseName: phaseName,
+		phaseName:         phaseName,
+		ownershipStrategy: NewBasicOwnershipStrategy(client),
 	}
 }
 
+// SetOwnershipStrategy permit to change the way to link the created objects to their parent
+// Default to BasicOwnershipStrategy
+func (h *BasicMultiPhaseStepReconcilerAction) SetOwnershipStrategy(strategy OwnershipStrategy) {
+	h.ownershipStrategy = strategy
+}
+
+// SetTrackedChildrenLists permit to set one empty list per type of children linked to their parent with tracking labels, like &corev1.SecretList{}
+// They are deleted when the parent is deleted. Create return error when it need tracking labels on a type not set here, because the child can't be deleted
+func (h *BasicMultiPhaseStepReconcilerAction) SetTrackedChildrenLists(lists ...client.ObjectList) {
+	h.trackedChildrenLists = lists
+}
+
+func (h *BasicMultiPhaseStepReconcilerAction) GetTrackedChildrenLists() []client.ObjectList {
+	// Return copies, because of the lists are filled when delete the children
+	lists := make([]client.ObjectList, 0, len(h.trackedChildrenLists))
+	for _, list := range h.trackedChildrenLists {
+		lists = append(lists, list.DeepCopyObject().(client.ObjectList))
+	}
+
+	return lists
+}
+
+// setOwner permit to link the child to its parent
+// Owner reference can't be used when the child is on another cluster, so it use tracking labels that need to be set with SetTrackedChildrenLists
+func (h *BasicMultiPhaseStepReconcilerAction) setOwner(ctx context.Context, o object.MultiPhaseObject, oChild client.Object) (err error) {
+	if TargetClientFromContext(ctx) != nil {
+		if err = SetTrackingReference(o, oChild, h.Client().Scheme()); err != nil {
+			return errors.Wrapf(err, "Error when set tracking reference on object '%s'", oChild.GetName())
+		}
+	} else if err = h.ownershipStrategy.SetOwner(o, oChild); err != nil {
+		return errors.Wrapf(err, "Error when set owner on object '%s'", oChild.GetName())
+	}
+
+	if _, isTracked := oChild.GetLabels()[ParentUIDLabel]; isTracked {
+		return h.checkTrackedChild(oChild)
+	}
+
+	return nil
+}
+
+// checkTrackedChild permit to check the type of child linked with tracking labels is set with SetTrackedChildrenLists
+func (h *BasicMultiPhaseStepReconcilerAction) checkTrackedChild(child client.Object) (err error) {
+	gvk, err := apiutil.GVKForObject(child, h.Client().Scheme())
+	if err != nil {
+		return errors.Wrapf(err, "Error when get GVK of object '%s'", child.GetName())
+	}
+
+	for _, list := range h.trackedChildrenLists {
+		listGvk, err := apiutil.GVKForObject(list, h.Client().Scheme())
+		if err != nil {
+			return errors.Wrapf(err, "Error when get GVK of list %T", list)
+		}
+		if listGvk.GroupVersion() == gvk.GroupVersion() && listGvk.Kind == gvk.Kind+"List" {
+			return nil
+		}
+	}
+
+	return NewTerminalError(errors.Errorf("Object '%s' of kind %s is linked to its parent with tracking labels, you need to set its list with SetTrackedChildrenLists to delete it with the parent", child.GetName(), gvk.Kind))
+}
+
 func (h *BasicMultiPhaseStepReconcilerAction) GetIgnoresDiff() []patch.CalculateOption {
 	return make([]patch.CalculateOption, 0)
 }
 
 func (h *BasicMultiPhaseStepReconcilerAction) Configure(ctx context.Context, req ctrl.Request, o object.MultiPhaseObject, logger *logrus.Entry) (res ctrl.Result, err error) {
-	conditions := o.GetStatus().GetConditions()
-
 	// Init condition
-	if condition.FindStatusCondition(conditions, h.conditionName.String()) == nil {
-		condition.SetStatusCondition(&conditions, metav1.Condition{
-			Type:   h.conditionName.String(),
-			Status: metav1.ConditionFalse,
-			Reason: "Initialize",
-		})
-	}
+	NewBasicConditionManager(o, o.GetStatus()).Init(h.conditionName)
 
 	// Init phase
 	o.GetStatus().SetPhaseName(h.GetPhaseName())
@@ -101,12 +157,16 @@ func (h *BasicMultiPhaseStepReconcilerAction) Read(ctx context.Context, o object
 
 func (h *BasicMultiPhaseStepReconcilerAction) Create(ctx context.Context, o object.MultiPhaseObject, data map[string]any, objects []client.Object, logger *logrus.Entry) (res ctrl.Result, err error) {
 
+	createdObjects := make([]client.Object, 0, len(objects))
+	defer func() {
+		h.recordObjectsEvent(o, "CreateCompleted", "created", createdObjects)
+	}()
+
 	for _, oChild := range objects {
 
 		// Set owner
-		err = ctrl.SetControllerReference(o, oChild, h.Client().Scheme())
-		if err != nil {
-			return res, errors.Wrapf(err, "Error when set owner reference on object '%s'", oChild.GetName())
+		if err = h.setOwner(ctx, o, oChild); err != nil {
+			return res, err
 		}
 
 		// Set diff 3-way annotations
@@ -114,11 +174,11 @@ func (h *BasicMultiPhaseStepReconcilerAction) Create(ctx context.Context, o obje
 			return res, errors.Wrapf(err, "Error when set annotation for 3-way diff on  object '%s'", oChild.GetName())
 		}
 
-		if err = h.Client().Create(ctx, oChild); err != nil {
+		if err = h.TargetClient(ctx).Create(ctx, oChild); err != nil {
 			return res, errors.Wrapf(err, "Error when create object '%s'", oChild.GetName())
 		}
 		logger.Debugf("Create object '%s' successfully", oChild.GetName())
-		h.Recorder().Eventf(o, corev1.EventTypeNormal, "CreateCompleted", "Object '%s' successfully created", oChild.GetName())
+		createdObjects = append(createdObjects, oChild)
 	}
 
 	return res, nil
@@ -126,12 +186,17 @@ func (h *BasicMultiPhaseStepReconcilerAction) Create(ctx context.Context, o obje
 
 func (h *BasicMultiPhaseStepReconcilerAction) Update(ctx context.Context, o object.MultiPhaseObject, data map[string]any, objects []client.Object, logger *logrus.Entry) (res ctrl.Result, err error) {
 
+	updatedObjects := make([]client.Object, 0, len(objects))
+	defer func() {
+		h.recordObjectsEvent(o, "UpdateCompleted", "updated", updatedObjects)
+	}()
+
 	for _, oChild := range objects {
-		if err = h.Client().Update(ctx, oChild); err != nil {
+		if err = h.TargetClient(ctx).Update(ctx, oChild); err != nil {
 			return res, errors.Wrapf(err, "Error when update object '%s'", oChild.GetName())
 		}
 		logger.Debugf("Update object '%s' successfully", oChild.GetName())
-		h.Recorder().Eventf(o, corev1.EventTypeNormal, "UpdateCompleted", "Object '%s' successfully updated", oChild.GetName())
+		updatedObjects = append(updatedObjects, oChild)
 	}
 
 	return res, nil
@@ -139,44 +204,36 @@ func (h *BasicMultiPhaseStepReconcilerAction) Update(ctx context.Context, o obje
 
 func (h *BasicMultiPhaseStepReconcilerAction) Delete(ctx context.Context, o object.MultiPhaseObject, data map[string]any, objects []client.Object, logger *logrus.Entry) (res ctrl.Result, err error) {
 
+	deletedObjects := make([]client.Object, 0, len(objects))
+	defer func() {
+		h.recordObjectsEvent(o, "DeleteCompleted", "deleted", deletedObjects)
+	}()
+
 	for _, oChild := range objects {
-		if err = h.Client().Delete(ctx, oChild); err != nil {
+		if err = h.TargetClient(ctx).Delete(ctx, oChild); err != nil {
 			return res, errors.Wrapf(err, "Error when delete object '%s'", oChild.GetName())
 		}
 		logger.Debugf("Delete object '%s' successfully", oChild.GetName())
-		h.Recorder().Eventf(o, corev1.EventTypeNormal, "DeleteCompleted", "Object '%s' successfully deleted", oChild.GetName())
+		deletedObjects = append(deletedObjects, oChild)
 	}
 
 	return res, nil
 }
 
 func (h *BasicMultiPhaseStepReconcilerAction) OnError(ctx context.Context, o object.MultiPhaseObject, data map[string]any, currentErr error, logger *logrus.Entry) (res ctrl.Result, err error) {
-	conditions := o.GetStatus().GetConditions()
+	NewBasicConditionManager(o, o.GetStatus()).MarkFalse(h.conditionName, GetConditionReasonFromError(currentErr), "%s", k8sstrings.ShortenString(currentErr.Error(), ShortenError))
 
-	condition.SetStatusCondition(&conditions, metav1.Condition{
-		Type:    h.conditionName.String(),
-		Status:  metav1.ConditionFalse,
-		Reason:  "Failed",
-		Message: k8sstrings.ShortenString(currentErr.Error(), ShortenError),
-	})
+	// Dependency not ready is requeued quietly
+	if !IsDependencyNotReadyError(currentErr) {
+		h.Recorder().Event(o, corev1.EventTypeWarning, "ReconcilerStepActionError", k8sstrings.ShortenString(currentErr.Error(), ShortenError))
+	}
 
-	h.Recorder().Event(o, corev1.EventTypeWarning, "ReconcilerStepActionError", k8sstrings.ShortenString(currentErr.Error(), ShortenError))
-	return res, currentErr
+	return ResultFromError(currentErr)
 
 }
 
 func (h *BasicMultiPhaseStepReconcilerAction) OnSuccess(ctx context.Context, o object.MultiPhaseObject, data map[string]any, diff MultiPhaseDiff, logger *logrus.Entry) (res ctrl.Result, err error) {
-	conditions := o.GetStatus().GetConditions()
-
-	// Update condition status if needed
-	if !condition.IsStatusConditionPresentAndEqual(conditions, h.conditionName.String(), metav1.ConditionTrue) {
-		condition.SetStatusCondition(&conditions, metav1.Condition{
-			Type:    h.conditionName.String(),
-			Reason:  "Success",
-			Status:  metav1.ConditionTrue,
-			Message: "Ready",
-		})
-	}
+	NewBasicConditionManager(o, o.GetStatus()).MarkTrue(h.conditionName, SuccessReason, "Ready")
 
 	return res, nil
 }
@@ -198,6 +255,11 @@ func (h *BasicMultiPhaseStepReconcilerAction) Diff(ctx context.Context, o object
 	toCreate := make([]client.Object, 0)
 
 	for _, expectedObject := range read.GetExpectedObjects() {
+		// Set owner, so the tracking labels are not removed on update
+		if err = h.setOwner(ctx, o, expectedObject); err != nil {
+			return diff, res, err
+		}
+
 		isFound := false
 		for i, currentObject := range tmpCurrentObjects {
 			// Need compare same object
@@ -205,7 +267,9 @@ func (h *BasicMultiPhaseStepReconcilerAction) Diff(ctx context.Context, o object
 				isFound = true
 
 				// Copy TypeMeta to work with some ignore rules like IgnorePDBSelector()
-				MustInjectTypeMeta(currentObject, expectedObject)
+				if err = InjectTypeMeta(currentObject, expectedObject); err != nil {
+					return diff, res, errors.Wrapf(err, "Error when inject TypeMeta on object '%s'", expectedObject.GetName())
+				}
 				patchResult, err := patch.DefaultPatchMaker.Calculate(currentObject, expectedObject, patchOptions...)
 				if err != nil {
 					return diff, res, errors.Wrapf(err, "Error when diffing object '%s'", currentObject.GetName())
@@ -2,12 +2,14 @@ package controller
 
 import (
 	"context"
-	"reflect"
+	"fmt"
+	"time"
 
 	"emperror.dev/errors"
 	"github.com/disaster37/k8s-objectmatcher/patch"
 	"github.com/disaster37/operator-sdk-extra/pkg/object"
 	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -37,8 +39,9 @@ func NewBasicMultiPhaseStepReconciler(client client.Client, logger *logrus.Entry
 func (h *BasicMultiPhaseStepReconciler) Reconcile(ctx context.Context, req ctrl.Request, o object.MultiPhaseObject, data map[string]interface{}, reconcilerAction MultiPhaseStepReconcilerAction, logger *logrus.Entry, ignoresDiff ...patch.CalculateOption) (res ctrl.Result, err error) {
 
 	var (
-		diff MultiPhaseDiff
-		read MultiPhaseRead
+		diff      MultiPhaseDiff
+		read      MultiPhaseRead
+		isBlocked bool
 	)
 
 	// Init logger
@@ -46,6 +49,15 @@ func (h *BasicMultiPhaseStepReconciler) Reconcile(ctx context.Context, req ctrl.
 		"step": reconcilerAction.GetPhaseName().String(),
 	})
 
+	// Get actions allowed by pause and maintenance window annotations
+	control := ReconcileControlFromContext(ctx)
+	if control == nil {
+		if control, err = NewReconcileControl(o, time.Now()); err != nil {
+			logger.Errorf("Error when compute reconcile control: %s", err.Error())
+			return reconcilerAction.OnError(ctx, o, data, NewTerminalError(errors.Wrap(err, ErrWhenComputeReconcileControl.Error())), logger)
+		}
+	}
+
 	// Configure
 	res, err = reconcilerAction.Configure(ctx, req, o, logger)
 	if err != nil {
@@ -57,6 +69,18 @@ func (h *BasicMultiPhaseStepReconciler) Reconcile(ctx context.Context, req ctrl.
 		return res, nil
 	}
 
+	// Manage the children on another cluster if needed
+	if targetAction, ok := reconcilerAction.(TargetClusterReconcilerAction); ok {
+		var targetClient client.Client
+		if targetClient, err = targetAction.GetTargetClient(ctx, o, logger); err != nil {
+			logger.Errorf("Error when call 'getTargetClient' from step reconciler: %s", err.Error())
+			return reconcilerAction.OnError(ctx, o, data, errors.Wrap(err, ErrWhenGetTargetClient.Error()), logger)
+		}
+		if targetClient != nil {
+			ctx = WithTargetClient(ctx, targetClient)
+		}
+	}
+
 	// Read resources
 	read, res, err = reconcilerAction.Read(ctx, o, data, logger)
 	if err != nil {
@@ -83,7 +107,11 @@ func (h *BasicMultiPhaseStepReconciler) Reconcile(ctx context.Context, req ctrl.
 	}
 
 	// Need create resources
-	if diff.NeedCreate() {
+	if diff.NeedCreate() && !control.CanCreate() {
+		logger.Infof("Skip 'create' from step reconciler because of pause or maintenance window")
+		control.Block(fmt.Sprintf("create on phase %s", reconcilerAction.GetPhaseName().String()))
+		isBlocked = true
+	} else if diff.NeedCreate() {
 		logger.Debug("Call 'create' from step reconciler")
 		res, err = reconcilerAction.Create(ctx, o, data, diff.GetObjectsToCreate(), logger)
 		if err != nil {
@@ -97,7 +125,11 @@ func (h *BasicMultiPhaseStepReconciler) Reconcile(ctx context.Context, req ctrl.
 	}
 
 	// Need update resources
-	if diff.NeedUpdate() {
+	if diff.NeedUpdate() && !control.CanUpdate() {
+		logger.Infof("Skip 'update' from step reconciler because of pause or maintenance window")
+		control.Block(fmt.Sprintf("update on phase %s", reconcilerAction.GetPhaseName().String()))
+		isBlocked = true
+	} else if diff.NeedUpdate() {
 		logger.Debug("Call 'update' from step reconciler")
 		res, err = reconcilerAction.Update(ctx, o, data, diff.GetObjectsToUpdate(), logger)
 		if err != nil {
@@ -111,7 +143,11 @@ func (h *BasicMultiPhaseStepReconciler) Reconcile(ctx context.Context, req ctrl.
 	}
 
 	// Need Delete
-	if diff.NeedDelete() {
+	if diff.NeedDelete() && !control.CanDelete() {
+		logger.Infof("Skip 'delete' from step reconciler because of pause or maintenance window")
+		control.Block(fmt.Sprintf("delete on phase %s", reconcilerAction.GetPhaseName().String()))
+		isBlocked = true
+	} else if diff.NeedDelete() {
 		logger.Debug("Call 'delete' from step reconciler")
 		res, err = reconcilerAction.Delete(ctx, o, data, diff.GetObjectsToDelete(), logger)
 		if err != nil {
@@ -124,6 +160,11 @@ func (h *BasicMultiPhaseStepReconciler) Reconcile(ctx context.Context, req ctrl.
 		}
 	}
 
+	// Not yet in expected state
+	if isBlocked {
+		return res, nil
+	}
+
 	res, err = reconcilerAction.OnSuccess(ctx, o, data, diff, logger)
 	if err != nil {
 		logger.Errorf("Error when call 'onSuccess' from step reconciler: %s", err.Error())
@@ -134,30 +175,35 @@ func (h *BasicMultiPhaseStepReconciler) Reconcile(ctx context.Context, req ctrl.
 	return res, nil
 }
 
-func MustInjectTypeMeta(src, dst client.Object) {
-	var (
-		rt reflect.Type
-	)
-
-	rt = reflect.TypeOf(src)
-	if rt.Kind() != reflect.Ptr {
-		panic("Resource must be pointer")
+// InjectTypeMeta permit to copy the TypeMeta from src to dst
+// Unstructured objects already have their GVK, so it only set it on dst if empty
+func InjectTypeMeta(src, dst client.Object) (err error) {
+	if isNilObject(src) {
+		return errors.New("Source object can't be nil")
 	}
-	rt = reflect.TypeOf(dst)
-	if rt.Kind() != reflect.Ptr {
-		panic("Resource must be pointer")
+	if isNilObject(dst) {
+		return errors.New("Destination object can't be nil")
 	}
 
-	rvSrc := reflect.ValueOf(src).Elem()
-	omSrc := rvSrc.FieldByName("TypeMeta")
-	if !omSrc.IsValid() {
-		panic("src must have field TypeMeta")
+	gvk := src.GetObjectKind().GroupVersionKind()
+	if _, ok := dst.(runtime.Unstructured); ok && !dst.GetObjectKind().GroupVersionKind().Empty() {
+		return nil
 	}
-	rvDst := reflect.ValueOf(dst).Elem()
-	omDst := rvDst.FieldByName("TypeMeta")
-	if !omDst.IsValid() {
-		panic("dst must have field TypeMeta")
+
+	dst.GetObjectKind().SetGroupVersionKind(gvk)
+	if dst.GetObjectKind().GroupVersionKind() != gvk {
+		return errors.Errorf("Object of type %T not support to set its GroupVersionKind", dst)
 	}
 
-	omDst.Set(omSrc)
+	return nil
+}
+
+// MustInjectTypeMeta permit to copy the TypeMeta from src to dst
+// It panic if TypeMeta can't be copied
+//
+// Deprecated: use InjectTypeMeta that return error instead
+func MustInjectTypeMeta(src, dst client.Object) {
+	if err := InjectTypeMeta(src, dst); err != nil {
+		panic(err)
+	}
 }
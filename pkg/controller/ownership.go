@@ -0,0 +1,182 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"emperror.dev/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var (
+	// ParentUIDLabel is the label set on tracked children with the UID of parent
+	ParentUIDLabel = fmt.Sprintf("%s/parent-uid", BaseAnnotation)
+
+	// ParentNameAnnotation is the annotation set on tracked children with the name of parent
+	ParentNameAnnotation = fmt.Sprintf("%s/parent-name", BaseAnnotation)
+
+	// ParentNamespaceAnnotation is the annotation set on tracked children with the namespace of parent
+	ParentNamespaceAnnotation = fmt.Sprintf("%s/parent-namespace", BaseAnnotation)
+
+	// ParentGroupKindAnnotation is the annotation set on tracked children with the group kind of parent, like "MyKind.my.domain.com"
+	ParentGroupKindAnnotation = fmt.Sprintf("%s/parent-group-kind", BaseAnnotation)
+)
+
+// OwnershipStrategy permit to link children to their parent
+type OwnershipStrategy interface {
+
+	// SetOwner permit to link the child to the parent
+	SetOwner(parent client.Object, child client.Object) (err error)
+}
+
+// BasicOwnershipStrategy is the basic implementation of OwnershipStrategy interface
+// It use controller owner reference when possible (parent is cluster scoped or child is on the same namespace).
+// Else it set tracking labels and annotations that point to the parent, because Kubernetes garbage collector not support cross namespace owner.
+// The tracked children need to be watched with NewTrackedChildEventHandler and deleted with DeleteTrackedChildren on finalizer.
+type BasicOwnershipStrategy struct {
+	client client.Client
+}
+
+// NewBasicOwnershipStrategy is the basic constructor of OwnershipStrategy interface
+func NewBasicOwnershipStrategy(client client.Client) OwnershipStrategy {
+	if client == nil {
+		panic("client can't be nil")
+	}
+
+	return &BasicOwnershipStrategy{
+		client: client,
+	}
+}
+
+func (h *BasicOwnershipStrategy) SetOwner(parent client.Object, child client.Object) (err error) {
+	isOwnerReference, err := h.canUseOwnerReference(parent, child)
+	if err != nil {
+		return err
+	}
+
+	if isOwnerReference {
+		return ctrl.SetControllerReference(parent, child, h.client.Scheme())
+	}
+
+	return SetTrackingReference(parent, child, h.client.Scheme())
+}
+
+// canUseOwnerReference permit to know if owner reference can be used between parent and child
+func (h *BasicOwnershipStrategy) canUseOwnerReference(parent client.Object, child client.Object) (bool, error) {
+	isParentNamespaced, err := h.client.IsObjectNamespaced(parent)
+	if err != nil {
+		return false, errors.Wrapf(err, "Error when check if parent '%s' is namespaced", parent.GetName())
+	}
+	if !isParentNamespaced {
+		return true, nil
+	}
+
+	isChildNamespaced, err := h.client.IsObjectNamespaced(child)
+	if err != nil {
+		return false, errors.Wrapf(err, "Error when check if object '%s' is namespaced", child.GetName())
+	}
+
+	return isChildNamespaced && child.GetNamespace() == parent.GetNamespace(), nil
+}
+
+// SetTrackingReference permit to set the tracking labels and annotations on child that point to the parent
+func SetTrackingReference(parent client.Object, child client.Object, scheme *runtime.Scheme) (err error) {
+	gvk, err := apiutil.GVKForObject(parent, scheme)
+	if err != nil {
+		return errors.Wrapf(err, "Error when get GVK of parent '%s'", parent.GetName())
+	}
+
+	labels := child.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[ParentUIDLabel] = string(parent.GetUID())
+	child.SetLabels(labels)
+
+	annotations := child.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ParentNameAnnotation] = parent.GetName()
+	annotations[ParentNamespaceAnnotation] = parent.GetNamespace()
+	annotations[ParentGroupKindAnnotation] = gvk.GroupKind().String()
+	child.SetAnnotations(annotations)
+
+	return nil
+}
+
+// TrackingLabelSelector permit to get the label selector to list the tracked children of parent
+func TrackingLabelSelector(parent client.Object) client.MatchingLabels {
+	return client.MatchingLabels{
+		ParentUIDLabel: string(parent.GetUID()),
+	}
+}
+
+// TrackedChildToParentMapFunc permit to map the tracked children to their parent of group kind
+func TrackedChildToParentMapFunc(parentGroupKind schema.GroupKind) handler.MapFunc {
+	return func(ctx context.Context, o client.Object) []reconcile.Request {
+		annotations := o.GetAnnotations()
+		if annotations[ParentGroupKindAnnotation] != parentGroupKind.String() || annotations[ParentNameAnnotation] == "" {
+			return nil
+		}
+
+		return []reconcile.Request{
+			{
+				NamespacedName: types.NamespacedName{
+					Name:      annotations[ParentNameAnnotation],
+					Namespace: annotations[ParentNamespaceAnnotation],
+				},
+			},
+		}
+	}
+}
+
+// NewTrackedChildEventHandler permit to get the event handler that enqueue the parent of group kind of the tracked children
+func NewTrackedChildEventHandler(parentGroupKind schema.GroupKind) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(TrackedChildToParentMapFunc(parentGroupKind))
+}
+
+// TrackedChildrenReconcilerAction is the interface of multiphase step actions which children can be linked to their parent with tracking labels
+// When the multiphase reconciler has finalizer, it call DeleteTrackedChildren with these lists on the target client of step before to remove the finalizer
+// BasicMultiPhaseStepReconcilerAction implement it with the lists set by SetTrackedChildrenLists
+type TrackedChildrenReconcilerAction interface {
+
+	// GetTrackedChildrenLists permit to get one empty list per children type, like &corev1.SecretList{}
+	GetTrackedChildrenLists() []client.ObjectList
+}
+
+// DeleteTrackedChildren permit to delete all tracked children of parent, on all namespaces
+// It need one empty list per children type, like &corev1.SecretList{}
+func DeleteTrackedChildren(ctx context.Context, c client.Client, parent client.Object, lists ...client.ObjectList) (err error) {
+	for _, list := range lists {
+		if err = c.List(ctx, list, TrackingLabelSelector(parent)); err != nil {
+			return errors.Wrapf(err, "Error when list tracked children of '%s'", parent.GetName())
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return errors.Wrap(err, "Error when extract tracked children from list")
+		}
+
+		for _, item := range items {
+			child, ok := item.(client.Object)
+			if !ok {
+				return errors.Errorf("Item of type %T is not client.Object", item)
+			}
+			if err = c.Delete(ctx, child); err != nil && !k8serrors.IsNotFound(err) {
+				return errors.Wrapf(err, "Error when delete tracked child '%s/%s'", child.GetNamespace(), child.GetName())
+			}
+		}
+	}
+
+	return nil
+}
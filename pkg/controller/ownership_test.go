@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestOwnershipClient(objects ...client.Object) client.Client {
+	restMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{corev1.SchemeGroupVersion})
+	restMapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	restMapper.Add(corev1.SchemeGroupVersion.WithKind("Secret"), meta.RESTScopeNamespace)
+	restMapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+
+	return fake.NewClientBuilder().WithRESTMapper(restMapper).WithObjects(objects...).Build()
+}
+
+func TestBasicOwnershipStrategy(t *testing.T) {
+	strategy := NewBasicOwnershipStrategy(newTestOwnershipClient())
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default", UID: "uid"}}
+
+	// When child is on same namespace
+	child := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: "default"}}
+	assert.NoError(t, strategy.SetOwner(parent, child))
+	assert.Len(t, child.OwnerReferences, 1)
+	assert.Empty(t, child.Labels)
+
+	// When child is on other namespace
+	child = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: "other"}}
+	assert.NoError(t, strategy.SetOwner(parent, child))
+	assert.Empty(t, child.OwnerReferences)
+	assert.Equal(t, "uid", child.Labels[ParentUIDLabel])
+	assert.Equal(t, "parent", child.Annotations[ParentNameAnnotation])
+	assert.Equal(t, "default", child.Annotations[ParentNamespaceAnnotation])
+	assert.Equal(t, "ConfigMap", child.Annotations[ParentGroupKindAnnotation])
+
+	// When child is cluster scoped
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "child"}}
+	assert.NoError(t, strategy.SetOwner(parent, namespace))
+	assert.Empty(t, namespace.OwnerReferences)
+	assert.Equal(t, "uid", namespace.Labels[ParentUIDLabel])
+
+	// When parent is cluster scoped
+	clusterParent := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "parent", UID: "uid2"}}
+	child = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: "other"}}
+	assert.NoError(t, strategy.SetOwner(clusterParent, child))
+	assert.Len(t, child.OwnerReferences, 1)
+
+	// When type is unknown by rest mapper
+	strategy = NewBasicOwnershipStrategy(fake.NewClientBuilder().Build())
+	assert.Error(t, strategy.SetOwner(parent, child))
+}
+
+func TestTrackedChildToParentMapFunc(t *testing.T) {
+	mapFunc := TrackedChildToParentMapFunc(schema.GroupKind{Kind: "ConfigMap"})
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default", UID: "uid"}}
+	child := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: "other"}}
+	assert.NoError(t, SetTrackingReference(parent, child, newTestOwnershipClient().Scheme()))
+
+	// When child of parent group kind
+	requests := mapFunc(context.Background(), child)
+	assert.Len(t, requests, 1)
+	assert.Equal(t, types.NamespacedName{Name: "parent", Namespace: "default"}, requests[0].NamespacedName)
+
+	// When child of other group kind
+	assert.Empty(t, TrackedChildToParentMapFunc(schema.GroupKind{Group: "apps", Kind: "Deployment"})(context.Background(), child))
+
+	// When not tracked child
+	assert.Empty(t, mapFunc(context.Background(), &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other"}}))
+}
+
+func TestDeleteTrackedChildren(t *testing.T) {
+	parent := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default", UID: "uid"}}
+	c := newTestOwnershipClient(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "child1", Namespace: "ns1", Labels: map[string]string{ParentUIDLabel: "uid"}}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "child2", Namespace: "ns2", Labels: map[string]string{ParentUIDLabel: "uid"}}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ns1", Labels: map[string]string{ParentUIDLabel: "other"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "child3", Labels: map[string]string{ParentUIDLabel: "uid"}}},
+	)
+
+	assert.NoError(t, DeleteTrackedChildren(context.Background(), c, parent, &corev1.SecretList{}, &corev1.NamespaceList{}))
+
+	secrets := &corev1.SecretList{}
+	assert.NoError(t, c.List(context.Background(), secrets))
+	assert.Len(t, secrets.Items, 1)
+	assert.Equal(t, "other", secrets.Items[0].Name)
+
+	namespaces := &corev1.NamespaceList{}
+	assert.NoError(t, c.List(context.Background(), namespaces, TrackingLabelSelector(parent)))
+	assert.Empty(t, namespaces.Items)
+}
@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/disaster37/operator-sdk-extra/pkg/apis/shared"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	PausedCondition                shared.ConditionName   = "Paused"
+	PausedReason                   shared.ConditionReason = "Paused"
+	OutsideMaintenanceWindowReason shared.ConditionReason = "OutsideMaintenanceWindow"
+)
+
+var (
+	// PauseAnnotation permit to pause the reconcile. The value can be "true" or a RFC3339 date to pause until
+	PauseAnnotation = fmt.Sprintf("%s/pause", BaseAnnotation)
+
+	// MaintenanceWindowAnnotation permit to only allow update and delete inside maintenance windows
+	// The value is a list of windows separated by ';' like "0 2 * * 6 4h"
+	MaintenanceWindowAnnotation = fmt.Sprintf("%s/maintenanceWindow", BaseAnnotation)
+)
+
+type reconcileControlKey struct{}
+
+// ReconcileControl permit to know the actions allowed on object depending of pause and maintenance windows annotations
+// Configure, read, diff and status are always allowed.
+// When paused, create, update and delete are skipped.
+// When maintenance windows are set, update and delete are only allowed inside them.
+// The finalizer deletion is always allowed.
+type ReconcileControl struct {
+	now                time.Time
+	isPaused           bool
+	pausedUntil        time.Time
+	maintenanceWindows []*MaintenanceWindow
+
+	mutex   sync.Mutex
+	blocked []string
+}
+
+// NewReconcileControl permit to compute the reconcile control from object annotations at the given time
+func NewReconcileControl(o client.Object, now time.Time) (control *ReconcileControl, err error) {
+	control = &ReconcileControl{
+		now: now,
+	}
+
+	pause := strings.TrimSpace(o.GetAnnotations()[PauseAnnotation])
+	switch pause {
+	case "", "false":
+	case "true":
+		control.isPaused = true
+	default:
+		pausedUntil, err := time.Parse(time.RFC3339, pause)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Annotation '%s' must be 'true', 'false' or RFC3339 date", PauseAnnotation)
+		}
+		if pausedUntil.After(now) {
+			control.isPaused = true
+			control.pausedUntil = pausedUntil
+		}
+	}
+
+	if windows := o.GetAnnotations()[MaintenanceWindowAnnotation]; windows != "" {
+		if control.maintenanceWindows, err = ParseMaintenanceWindows(windows); err != nil {
+			return nil, errors.Wrapf(err, "Error when parse annotation '%s'", MaintenanceWindowAnnotation)
+		}
+	}
+
+	return control, nil
+}
+
+// WithReconcileControl permit to store the reconcile control on context
+func WithReconcileControl(ctx context.Context, control *ReconcileControl) context.Context {
+	return context.WithValue(ctx, reconcileControlKey{}, control)
+}
+
+// ReconcileControlFromContext permit to get the reconcile control from context
+// It return nil if not found
+func ReconcileControlFromContext(ctx context.Context) *ReconcileControl {
+	control, _ := ctx.Value(reconcileControlKey{}).(*ReconcileControl)
+	return control
+}
+
+// IsPaused permit to know if reconcile is paused
+func (h *ReconcileControl) IsPaused() bool {
+	return h.isPaused
+}
+
+// IsInMaintenanceWindow permit to know if we are inside a maintenance window
+// It return true if no maintenance window is set
+func (h *ReconcileControl) IsInMaintenanceWindow() bool {
+	if len(h.maintenanceWindows) == 0 {
+		return true
+	}
+
+	for _, window := range h.maintenanceWindows {
+		if window.Contains(h.now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanCreate permit to know if objects can be created
+func (h *ReconcileControl) CanCreate() bool {
+	return !h.isPaused
+}
+
+// CanUpdate permit to know if objects can be updated
+func (h *ReconcileControl) CanUpdate() bool {
+	return !h.isPaused && h.IsInMaintenanceWindow()
+}
+
+// CanDelete permit to know if objects can be deleted
+func (h *ReconcileControl) CanDelete() bool {
+	return !h.isPaused && h.IsInMaintenanceWindow()
+}
+
+// Block permit to record an action skipped because of pause or maintenance window
+func (h *ReconcileControl) Block(action string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.blocked = append(h.blocked, action)
+}
+
+// IsBlocked permit to know if some actions have been skipped
+func (h *ReconcileControl) IsBlocked() bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return len(h.blocked) > 0
+}
+
+// RequeueAfter permit to get the delay before the blocked actions can be run
+// It return 0 when paused without date, the object will be reconciled when the annotation change
+func (h *ReconcileControl) RequeueAfter() time.Duration {
+	if h.isPaused {
+		if h.pausedUntil.IsZero() {
+			return 0
+		}
+		return h.pausedUntil.Sub(h.now)
+	}
+
+	if h.IsInMaintenanceWindow() {
+		return 0
+	}
+
+	var next time.Time
+	for _, window := range h.maintenanceWindows {
+		windowNext := window.Next(h.now)
+		if !windowNext.IsZero() && (next.IsZero() || windowNext.Before(next)) {
+			next = windowNext
+		}
+	}
+	if next.IsZero() {
+		return 0
+	}
+
+	return next.Sub(h.now)
+}
+
+// SetCondition permit to report the pause state on the Paused condition
+// Outside maintenance window, the condition is only set when some actions have been skipped
+// The condition is removed when all actions are allowed
+func (h *ReconcileControl) SetCondition(conditionManager ConditionManager) {
+	switch {
+	case h.isPaused && h.pausedUntil.IsZero():
+		conditionManager.MarkTrue(PausedCondition, PausedReason, "Reconcile is paused")
+	case h.isPaused:
+		conditionManager.MarkTrue(PausedCondition, PausedReason, "Reconcile is paused until %s", h.pausedUntil.Format(time.RFC3339))
+	case !h.IsInMaintenanceWindow() && h.IsBlocked():
+		conditionManager.MarkTrue(PausedCondition, OutsideMaintenanceWindowReason, "Update and delete are paused until next maintenance window at %s", h.now.Add(h.RequeueAfter()).UTC().Format(time.RFC3339))
+	default:
+		conditionManager.Remove(PausedCondition)
+	}
+}
+
+// GetBlockedActions permit to get the actions skipped because of pause or maintenance window
+func (h *ReconcileControl) GetBlockedActions() []string {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return append([]string{}, h.blocked...)
+}
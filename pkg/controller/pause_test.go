@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/disaster37/k8s-objectmatcher/patch"
+	"github.com/disaster37/operator-sdk-extra/pkg/object"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type testPauseStepAction struct {
+	MultiPhaseStepReconcilerAction
+	diff MultiPhaseDiff
+}
+
+func (h *testPauseStepAction) Read(ctx context.Context, o object.MultiPhaseObject, data map[string]any, logger *logrus.Entry) (read MultiPhaseRead, res ctrl.Result, err error) {
+	return NewBasicMultiPhaseRead(), res, nil
+}
+
+func (h *testPauseStepAction) Diff(ctx context.Context, o object.MultiPhaseObject, read MultiPhaseRead, data map[string]any, logger *logrus.Entry, ignoreDiff ...patch.CalculateOption) (diff MultiPhaseDiff, res ctrl.Result, err error) {
+	return h.diff, res, nil
+}
+
+func TestNewReconcileControl(t *testing.T) {
+	now := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	o := &testMultiPhaseObject{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+	}
+
+	// When no annotations
+	control, err := NewReconcileControl(o, now)
+	assert.NoError(t, err)
+	assert.False(t, control.IsPaused())
+	assert.True(t, control.CanCreate())
+	assert.True(t, control.CanUpdate())
+	assert.True(t, control.CanDelete())
+	assert.Equal(t, time.Duration(0), control.RequeueAfter())
+
+	// When paused
+	o.Annotations = map[string]string{PauseAnnotation: "true"}
+	control, err = NewReconcileControl(o, now)
+	assert.NoError(t, err)
+	assert.True(t, control.IsPaused())
+	assert.False(t, control.CanCreate())
+	assert.False(t, control.CanUpdate())
+	assert.False(t, control.CanDelete())
+	assert.Equal(t, time.Duration(0), control.RequeueAfter())
+
+	// When paused until date
+	o.Annotations = map[string]string{PauseAnnotation: "2024-01-02T11:00:00Z"}
+	control, err = NewReconcileControl(o, now)
+	assert.NoError(t, err)
+	assert.True(t, control.IsPaused())
+	assert.Equal(t, time.Hour, control.RequeueAfter())
+
+	// When pause date is past
+	o.Annotations = map[string]string{PauseAnnotation: "2024-01-02T09:00:00Z"}
+	control, err = NewReconcileControl(o, now)
+	assert.NoError(t, err)
+	assert.False(t, control.IsPaused())
+
+	// When bad pause annotation
+	o.Annotations = map[string]string{PauseAnnotation: "tomorrow"}
+	_, err = NewReconcileControl(o, now)
+	assert.Error(t, err)
+
+	// When outside maintenance window
+	o.Annotations = map[string]string{MaintenanceWindowAnnotation: "0 2 * * * 4h"}
+	control, err = NewReconcileControl(o, now)
+	assert.NoError(t, err)
+	assert.True(t, control.CanCreate())
+	assert.False(t, control.CanUpdate())
+	assert.False(t, control.CanDelete())
+	assert.Equal(t, 16*time.Hour, control.RequeueAfter())
+
+	// When inside maintenance window
+	o.Annotations = map[string]string{MaintenanceWindowAnnotation: "0 2 * * * 4h;0 9 * * * 2h"}
+	control, err = NewReconcileControl(o, now)
+	assert.NoError(t, err)
+	assert.True(t, control.CanUpdate())
+
+	// When bad maintenance window
+	o.Annotations = map[string]string{MaintenanceWindowAnnotation: "0 2 * * *"}
+	_, err = NewReconcileControl(o, now)
+	assert.Error(t, err)
+}
+
+func TestReconcileControlSetCondition(t *testing.T) {
+	now := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	o := &testMultiPhaseObject{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test",
+			Annotations: map[string]string{PauseAnnotation: "true"},
+		},
+	}
+	conditionManager := NewBasicConditionManager(o, o.GetStatus())
+
+	// When paused
+	control, err := NewReconcileControl(o, now)
+	assert.NoError(t, err)
+	control.SetCondition(conditionManager)
+	assert.True(t, conditionManager.IsTrue(PausedCondition))
+	assert.Equal(t, PausedReason.String(), conditionManager.Get(PausedCondition).Reason)
+
+	// When outside maintenance window without skipped actions
+	o.Annotations = map[string]string{MaintenanceWindowAnnotation: "0 2 * * * 4h"}
+	control, err = NewReconcileControl(o, now)
+	assert.NoError(t, err)
+	control.SetCondition(conditionManager)
+	assert.Nil(t, conditionManager.Get(PausedCondition))
+
+	// When outside maintenance window with skipped actions
+	control.Block("update")
+	control.SetCondition(conditionManager)
+	assert.True(t, conditionManager.IsTrue(PausedCondition))
+	assert.Equal(t, OutsideMaintenanceWindowReason.String(), conditionManager.Get(PausedCondition).Reason)
+	assert.Equal(t, "Update and delete are paused until next maintenance window at 2024-01-03T02:00:00Z", conditionManager.Get(PausedCondition).Message)
+
+	// When resumed
+	o.Annotations = nil
+	control, err = NewReconcileControl(o, now)
+	assert.NoError(t, err)
+	control.SetCondition(conditionManager)
+	assert.Nil(t, conditionManager.Get(PausedCondition))
+}
+
+func TestBasicMultiPhaseStepReconcilerPause(t *testing.T) {
+	o := &testMultiPhaseObject{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test",
+			Namespace:   "default",
+			Annotations: map[string]string{PauseAnnotation: "true"},
+		},
+	}
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"}}
+	fakeClient := fake.NewClientBuilder().WithObjects(cm).Build()
+	recorder := record.NewFakeRecorder(10)
+	logger := logrus.NewEntry(logrus.New())
+	reconciler := NewBasicMultiPhaseStepReconciler(fakeClient, logger, recorder)
+
+	diff := NewBasicMultiPhaseDiff()
+	diff.SetObjectsToUpdate([]client.Object{&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"}, Data: map[string]string{"foo": "bar"}}})
+	action := &testPauseStepAction{
+		MultiPhaseStepReconcilerAction: NewBasicMultiPhaseStepReconcilerAction(fakeClient, "test", "TestReady", recorder),
+		diff:                           diff,
+	}
+
+	// When paused, update is skipped and OnSuccess is not called
+	control, err := NewReconcileControl(o, time.Now())
+	assert.NoError(t, err)
+	_, err = reconciler.Reconcile(WithReconcileControl(context.Background(), control), ctrl.Request{}, o, nil, action, logger)
+	assert.NoError(t, err)
+	assert.True(t, control.IsBlocked())
+	assert.Equal(t, []string{"update on phase test"}, control.GetBlockedActions())
+	assert.False(t, IsConditionTrue(o.GetStatus(), "TestReady"))
+	assert.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(cm), cm))
+	assert.Empty(t, cm.Data)
+
+	// When resumed, the control is computed from annotations
+	o.Annotations = nil
+	_, err = reconciler.Reconcile(context.Background(), ctrl.Request{}, o, nil, action, logger)
+	assert.NoError(t, err)
+	assert.True(t, IsConditionTrue(o.GetStatus(), "TestReady"))
+	assert.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(cm), cm))
+	assert.Equal(t, "bar", cm.Data["foo"])
+}
@@ -1,6 +1,9 @@
 package controller
 
 import (
+	"context"
+	"time"
+
 	"emperror.dev/errors"
 	"github.com/disaster37/operator-sdk-extra/pkg/apis/shared"
 	"github.com/sirupsen/logrus"
@@ -12,6 +15,7 @@ var (
 	ErrWhenCallConfigureFromReconciler      = errors.Sentinel("Error when call 'configure' from reconciler")
 	ErrWhenCallReadFromReconciler           = errors.Sentinel("Error when call 'read' from reconciler")
 	ErrWhenCallDeleteFromReconciler         = errors.Sentinel("Error when call 'delete' from reconciler")
+	ErrWhenCallFinalizeFromReconciler       = errors.Sentinel("Error when call 'finalize' from reconciler")
 	ErrWhenCallDiffFromReconciler           = errors.Sentinel("Error when call 'diff' from reconciler")
 	ErrWhenCallCreateFromReconciler         = errors.Sentinel("Error when call 'create' from reconciler")
 	ErrWhenCallUpdateFromReconciler         = errors.Sentinel("Error when call 'update' from reconciler")
@@ -21,8 +25,14 @@ var (
 	ErrWhenAddFinalizer                     = errors.Sentinel("Error when add finalizer")
 	ErrWhenDeleteFinalizer                  = errors.Sentinel("Error when delete finalizer")
 	ErrWhenGetObjectStatus                  = errors.Sentinel("Error when get object status")
+	ErrWhenComputeReconcileControl          = errors.Sentinel("Error when compute reconcile control from annotations")
+	ErrWhenGetTargetClient                  = errors.Sentinel("Error when get target client from reconciler")
+	ErrWhenDeleteTrackedChildren            = errors.Sentinel("Error when delete tracked children")
 )
 
+// DefaultReconcileDelay is the default delay waited at the beginning of each reconcile loop, to be sure status is propaged througout ETCD
+const DefaultReconcileDelay = time.Second
+
 // BaseReconciler is the interface for all reconciler
 type BaseReconciler interface {
 
@@ -38,6 +48,8 @@ type DefaultBaseReconciler struct {
 	recorder record.EventRecorder
 }
 
+// NewBaseReconciler permit to get the base reconciler
+// The client is wrapped to record the Secrets and ConfigMaps read during reconcile, see NewTrackingClient
 func NewBaseReconciler(client client.Client, recorder record.EventRecorder) BaseReconciler {
 
 	if recorder == nil {
@@ -49,7 +61,7 @@ func NewBaseReconciler(client client.Client, recorder record.EventRecorder) Base
 	}
 
 	return &DefaultBaseReconciler{
-		client:   client,
+		client:   NewTrackingClient(client),
 		recorder: recorder,
 	}
 }
@@ -66,8 +78,10 @@ func (h *DefaultBaseReconciler) Recorder() record.EventRecorder {
 // It also provide attributes needed by all reconciler
 type BasicReconciler struct {
 	BaseReconciler
-	finalizer shared.FinalizerName
-	logger    *logrus.Entry
+	finalizer          shared.FinalizerName
+	logger             *logrus.Entry
+	reconcileDelay     time.Duration
+	dependencyRegistry *DependencyRegistry
 }
 
 func NewBasicReconciler(client client.Client, recorder record.EventRecorder, finalizer shared.FinalizerName, logger *logrus.Entry) BasicReconciler {
@@ -76,12 +90,25 @@ func NewBasicReconciler(client client.Client, recorder record.EventRecorder, fin
 	}
 
 	return BasicReconciler{
-		BaseReconciler: NewBaseReconciler(client, recorder),
-		finalizer:      finalizer,
-		logger:         logger,
+		BaseReconciler:     NewBaseReconciler(client, recorder),
+		finalizer:          finalizer,
+		logger:             logger,
+		reconcileDelay:     DefaultReconcileDelay,
+		dependencyRegistry: NewDependencyRegistry(),
 	}
 }
 
+// SetReconcileDelay permit to set the delay waited at the beginning of each reconcile loop
+// Tests with fake client can set it to 0 to run reconcile loops without waiting
+func (h *BasicReconciler) SetReconcileDelay(delay time.Duration) {
+	h.reconcileDelay = delay
+}
+
+// DependencyRegistry permit to get the registry of the Secrets and ConfigMaps read during reconcile
+func (h *BasicReconciler) DependencyRegistry() *DependencyRegistry {
+	return h.dependencyRegistry
+}
+
 // BasicReconcilerAction provide attribute needed by all reconciler action
 type BasicReconcilerAction struct {
 	BaseReconciler
@@ -99,3 +126,13 @@ func NewBasicReconcilerAction(client client.Client, recorder record.EventRecorde
 		conditionName:  conditionName,
 	}
 }
+
+// TargetClient permit to get the client of the cluster where the children are managed
+// It return the client of target cluster if set on context with WithTargetClient, else the default client
+func (h *BasicReconcilerAction) TargetClient(ctx context.Context) client.Client {
+	if c := TargetClientFromContext(ctx); c != nil {
+		return c
+	}
+
+	return h.Client()
+}
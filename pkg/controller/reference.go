@@ -0,0 +1,203 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"emperror.dev/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reference declare that the field of object references another object (the target) by name and namespace
+// The framework use it to index the objects by their references, and to reconcile them when the target change
+type Reference struct {
+	// Name is the name of reference, like 'elasticsearchRef'. It need to be unique per object type
+	Name string
+
+	// Object is the type of object that have the reference, like &Role{}
+	Object client.Object
+
+	// Target is the type of referenced object, like &Elasticsearch{}
+	Target client.Object
+
+	// IsClusterScopedTarget need to be true when the target is cluster scoped
+	// Else the namespace of object is used when the namespace of reference is empty
+	IsClusterScopedTarget bool
+
+	// GetReferences permit to get the referenced objects from the object
+	// It return nil when the reference is not set
+	GetReferences func(o client.Object) []types.NamespacedName
+}
+
+// NewReference permit to declare the reference from typed function
+func NewReference[T client.Object](name string, o T, target client.Object, getReferences func(o T) []types.NamespacedName) Reference {
+	return Reference{
+		Name:   name,
+		Object: o,
+		Target: target,
+		GetReferences: func(o client.Object) []types.NamespacedName {
+			typedObject, ok := o.(T)
+			if !ok {
+				return nil
+			}
+			return getReferences(typedObject)
+		},
+	}
+}
+
+// Validate permit to check that the reference is well declared
+func (h Reference) Validate() (err error) {
+	if h.Name == "" {
+		return errors.New("Reference name can't be empty")
+	}
+	if h.Object == nil {
+		return errors.Errorf("Object of reference %s can't be nil", h.Name)
+	}
+	if h.Target == nil {
+		return errors.Errorf("Target of reference %s can't be nil", h.Name)
+	}
+	if h.GetReferences == nil {
+		return errors.Errorf("GetReferences of reference %s can't be nil", h.Name)
+	}
+
+	return nil
+}
+
+// IndexName is the name of field index that store the references
+func (h Reference) IndexName() string {
+	return fmt.Sprintf("%s/reference.%s", BaseAnnotation, h.Name)
+}
+
+// Extract permit to get the index values of object, like 'namespace/name'. It is the client.IndexerFunc of reference
+// It can be used on tests with the fake client option 'WithIndex'
+func (h Reference) Extract(o client.Object) []string {
+	references := h.GetReferences(o)
+	if len(references) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(references))
+	for _, reference := range references {
+		values = append(values, h.normalize(o, reference).String())
+	}
+
+	return values
+}
+
+// Indexer permit to get the indexer that register the field index of reference on manager
+func (h Reference) Indexer() Indexer {
+	return func(mgr ctrl.Manager) error {
+		if err := mgr.GetFieldIndexer().IndexField(context.Background(), h.Object, h.IndexName(), h.Extract); err != nil {
+			return errors.Wrapf(err, "Error when add index of reference %s", h.Name)
+		}
+
+		return nil
+	}
+}
+
+// MapFunc permit to get the objects that reference the target, from field index
+func (h Reference) MapFunc(c client.Client) handler.MapFunc {
+	return func(ctx context.Context, target client.Object) []reconcile.Request {
+		logger := logrus.WithFields(logrus.Fields{
+			"reference": h.Name,
+			"name":      target.GetName(),
+			"namespace": target.GetNamespace(),
+		})
+
+		list, err := h.newObjectList(c)
+		if err != nil {
+			logger.Errorf("Error when get list of objects that reference target: %s", err.Error())
+			return nil
+		}
+
+		key := types.NamespacedName{Namespace: target.GetNamespace(), Name: target.GetName()}
+		if err = c.List(ctx, list, client.MatchingFields{h.IndexName(): key.String()}); err != nil {
+			logger.Errorf("Error when list objects that reference target: %s", err.Error())
+			return nil
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			logger.Errorf("Error when extract objects that reference target: %s", err.Error())
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(items))
+		for _, item := range items {
+			o, ok := item.(client.Object)
+			if !ok {
+				continue
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}})
+		}
+
+		return requests
+	}
+}
+
+// EventHandler permit to get the event handler that enqueue the objects that reference the target
+func (h Reference) EventHandler(c client.Client) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(h.MapFunc(c))
+}
+
+// normalize permit to set the namespace of object on reference when needed
+func (h Reference) normalize(o client.Object, reference types.NamespacedName) types.NamespacedName {
+	if h.IsClusterScopedTarget {
+		reference.Namespace = ""
+	} else if reference.Namespace == "" {
+		reference.Namespace = o.GetNamespace()
+	}
+
+	return reference
+}
+
+// newObjectList permit to get empty list of the object type
+func (h Reference) newObjectList(c client.Client) (list client.ObjectList, err error) {
+	gvk, err := apiutil.GVKForObject(h.Object, c.Scheme())
+	if err != nil {
+		return nil, errors.Wrap(err, "Error when get GVK of object")
+	}
+	listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+
+	if _, isUnstructured := h.Object.(*unstructured.Unstructured); isUnstructured {
+		u := &unstructured.UnstructuredList{}
+		u.SetGroupVersionKind(listGVK)
+		return u, nil
+	}
+
+	o, err := c.Scheme().New(listGVK)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error when create list %s", listGVK.String())
+	}
+	list, ok := o.(client.ObjectList)
+	if !ok {
+		return nil, errors.Errorf("Type %T is not client.ObjectList", o)
+	}
+
+	return list, nil
+}
+
+// SetupReferencesWithManager permit to register the field indexes of references on manager, and to watch their targets on controller builder
+// The objects are reconciled when the target that they reference change
+func SetupReferencesWithManager(mgr ctrl.Manager, b *builder.Builder, references ...Reference) (err error) {
+	for _, reference := range references {
+		if err = reference.Validate(); err != nil {
+			return err
+		}
+		if err = reference.Indexer()(mgr); err != nil {
+			return err
+		}
+		b.Watches(reference.Target, reference.EventHandler(mgr.GetClient()))
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func newTestConfigMapReference() Reference {
+	return NewReference("configMapRef", &corev1.Pod{}, &corev1.ConfigMap{}, func(o *corev1.Pod) []types.NamespacedName {
+		references := make([]types.NamespacedName, 0)
+		for _, volume := range o.Spec.Volumes {
+			if volume.ConfigMap != nil {
+				references = append(references, types.NamespacedName{Name: volume.ConfigMap.Name})
+			}
+		}
+		return references
+	})
+}
+
+func newTestPodWithConfigMap(name string, namespace string, configMaps ...string) *corev1.Pod {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	for _, configMap := range configMaps {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: configMap,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: configMap}},
+			},
+		})
+	}
+	return pod
+}
+
+func TestReferenceValidate(t *testing.T) {
+	assert.NoError(t, newTestConfigMapReference().Validate())
+	assert.Error(t, Reference{}.Validate())
+	assert.Error(t, Reference{Name: "test"}.Validate())
+	assert.Error(t, Reference{Name: "test", Object: &corev1.Pod{}}.Validate())
+	assert.Error(t, Reference{Name: "test", Object: &corev1.Pod{}, Target: &corev1.ConfigMap{}}.Validate())
+}
+
+func TestReferenceExtract(t *testing.T) {
+	reference := newTestConfigMapReference()
+
+	// When no reference
+	assert.Empty(t, reference.Extract(&corev1.Pod{}))
+
+	// When references without namespace
+	assert.Equal(t, []string{"default/cm1", "default/cm2"}, reference.Extract(newTestPodWithConfigMap("pod", "default", "cm1", "cm2")))
+
+	// When reference have namespace
+	reference.GetReferences = func(o client.Object) []types.NamespacedName {
+		return []types.NamespacedName{{Namespace: "other", Name: "cm1"}}
+	}
+	assert.Equal(t, []string{"other/cm1"}, reference.Extract(newTestPodWithConfigMap("pod", "default")))
+
+	// When target is cluster scoped
+	reference = NewReference("nodeRef", &corev1.Pod{}, &corev1.Node{}, func(o *corev1.Pod) []types.NamespacedName {
+		return []types.NamespacedName{{Name: o.Spec.NodeName}}
+	})
+	reference.IsClusterScopedTarget = true
+	assert.Equal(t, []string{"/node1"}, reference.Extract(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default"}, Spec: corev1.PodSpec{NodeName: "node1"}}))
+
+	// When object has not the expected type
+	assert.Empty(t, reference.Extract(&corev1.ConfigMap{}))
+}
+
+func TestReferenceMapFunc(t *testing.T) {
+	reference := newTestConfigMapReference()
+	fakeClient := fake.NewClientBuilder().
+		WithIndex(&corev1.Pod{}, reference.IndexName(), reference.Extract).
+		WithObjects(
+			newTestPodWithConfigMap("pod1", "default", "cm1"),
+			newTestPodWithConfigMap("pod2", "default", "cm1", "cm2"),
+			newTestPodWithConfigMap("pod3", "other", "cm1"),
+			newTestPodWithConfigMap("pod4", "default"),
+		).
+		Build()
+	mapFunc := reference.MapFunc(fakeClient)
+
+	// When target is referenced
+	requests := mapFunc(context.Background(), &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm1", Namespace: "default"}})
+	assert.ElementsMatch(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "default", Name: "pod1"}},
+		{NamespacedName: types.NamespacedName{Namespace: "default", Name: "pod2"}},
+	}, requests)
+
+	// When target is not referenced
+	requests = mapFunc(context.Background(), &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm3", Namespace: "default"}})
+	assert.Empty(t, requests)
+
+	// When index not exist
+	requests = reference.MapFunc(fake.NewClientBuilder().Build())(context.Background(), &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm1", Namespace: "default"}})
+	assert.Empty(t, requests)
+}
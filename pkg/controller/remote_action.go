@@ -10,8 +10,6 @@ import (
 	"github.com/disaster37/operator-sdk-extra/pkg/object"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
-	condition "k8s.io/apimachinery/pkg/api/meta"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	k8sstrings "k8s.io/utils/strings"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -76,16 +74,8 @@ func (h *BasicRemoteReconcilerAction[k8sObject, apiObject, apiClient]) GetRemote
 }
 
 func (h *BasicRemoteReconcilerAction[k8sObject, apiObject, apiClient]) Configure(ctx context.Context, o object.RemoteObject, data map[string]any, handler RemoteExternalReconciler[k8sObject, apiObject, apiClient], logger *logrus.Entry) (res ctrl.Result, err error) {
-	conditions := o.GetStatus().GetConditions()
-
 	// Init condition
-	if condition.FindStatusCondition(conditions, h.conditionName.String()) == nil {
-		condition.SetStatusCondition(&conditions, metav1.Condition{
-			Type:   h.conditionName.String(),
-			Status: metav1.ConditionFalse,
-			Reason: "Initialize",
-		})
-	}
+	NewBasicConditionManager(o, o.GetStatus()).Init(h.conditionName)
 
 	return res, nil
 }
@@ -164,35 +154,23 @@ func (h *BasicRemoteReconcilerAction[k8sObject, apiObject, apiClient]) Delete(ct
 
 func (h *BasicRemoteReconcilerAction[k8sObject, apiObject, apiClient]) OnError(ctx context.Context, o object.RemoteObject, data map[string]any, handler RemoteExternalReconciler[k8sObject, apiObject, apiClient], currentErr error, logger *logrus.Entry) (res ctrl.Result, err error) {
 
-	o.GetStatus().SetIsOnError(true)
-	o.GetStatus().SetLastErrorMessage(k8sstrings.ShortenString(currentErr.Error(), ShortenError))
 	o.GetStatus().SetIsSync(false)
 
-	conditions := o.GetStatus().GetConditions()
-
-	condition.SetStatusCondition(&conditions, metav1.Condition{
-		Type:    h.conditionName.String(),
-		Status:  metav1.ConditionFalse,
-		Reason:  "Failed",
-		Message: k8sstrings.ShortenString(currentErr.Error(), ShortenError),
-	})
+	NewBasicConditionManager(o, o.GetStatus()).MarkFalse(h.conditionName, GetConditionReasonFromError(currentErr), "%s", k8sstrings.ShortenString(currentErr.Error(), ShortenError))
 
-	h.Recorder().Event(o, corev1.EventTypeWarning, "ReconcilerActionError", k8sstrings.ShortenString(currentErr.Error(), ShortenError))
+	// Dependency not ready is requeued quietly
+	if !IsDependencyNotReadyError(currentErr) {
+		o.GetStatus().SetIsOnError(true)
+		o.GetStatus().SetLastErrorMessage(k8sstrings.ShortenString(currentErr.Error(), ShortenError))
+		h.Recorder().Event(o, corev1.EventTypeWarning, "ReconcilerActionError", k8sstrings.ShortenString(currentErr.Error(), ShortenError))
+	}
 
-	return res, currentErr
+	return ResultFromError(currentErr)
 }
 
 func (h *BasicRemoteReconcilerAction[k8sObject, apiObject, apiClient]) OnSuccess(ctx context.Context, o object.RemoteObject, data map[string]any, handler RemoteExternalReconciler[k8sObject, apiObject, apiClient], diff RemoteDiff[apiObject], logger *logrus.Entry) (res ctrl.Result, err error) {
 
-	conditions := o.GetStatus().GetConditions()
-	if !condition.IsStatusConditionPresentAndEqual(conditions, h.conditionName.String(), metav1.ConditionTrue) {
-		condition.SetStatusCondition(&conditions, metav1.Condition{
-			Type:   h.conditionName.String(),
-			Status: metav1.ConditionTrue,
-			Reason: "Ready",
-		})
-	}
-	o.GetStatus().SetConditions(conditions)
+	NewBasicConditionManager(o, o.GetStatus()).MarkTrue(h.conditionName, ReadyReason, "")
 
 	o.GetStatus().SetIsOnError(false)
 	o.GetStatus().SetIsSync(true)
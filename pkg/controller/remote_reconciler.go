@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"emperror.dev/errors"
@@ -24,6 +25,12 @@ type RemoteReconciler[k8sObject comparable, apiObject comparable, apiClient any]
 
 	// Reconcile permit to reconcile the step (one K8s resource)
 	Reconcile(ctx context.Context, req ctrl.Request, o object.RemoteObject, data map[string]interface{}, reconciler RemoteReconcilerAction[k8sObject, apiObject, apiClient]) (res ctrl.Result, err error)
+
+	// SetReconcileDelay permit to set the delay waited at the beginning of each reconcile loop
+	SetReconcileDelay(delay time.Duration)
+
+	// DependencyRegistry permit to get the registry of dependencies read during reconcile, to watch them with SetupTrackedDependenciesWithManager
+	DependencyRegistry() *DependencyRegistry
 }
 
 // BasicRemoteReconciler is the basic implementation of RemoteReconciler interface
@@ -63,12 +70,20 @@ func (h *BasicRemoteReconciler[k8sObject, apiObject, apiClient]) Reconcile(ctx c
 	logger.Infof("Starting reconcile loop")
 	defer logger.Info("Finish reconcile loop")
 
+	// Handle terminal, transient and dependency errors when OnError not already do it
+	defer func() {
+		if err != nil {
+			res, err = ResultFromError(err)
+		}
+	}()
+
 	// Wait few second to be sure status is propaged througout ETCD
-	time.Sleep(time.Second * 1)
+	time.Sleep(h.reconcileDelay)
 
 	// Get current resource
 	if err = h.Client().Get(ctx, req.NamespacedName, o); err != nil {
 		if k8serrors.IsNotFound(err) {
+			untrackDependencies(h.Client(), h.dependencyRegistry, o, req.NamespacedName)
 			return res, nil
 		}
 		logger.Errorf("Error when get object: %s", err.Error())
@@ -90,15 +105,21 @@ func (h *BasicRemoteReconciler[k8sObject, apiObject, apiClient]) Reconcile(ctx c
 	}
 
 	// Handle status update if exist
-	if getObjectStatus(o) != nil {
-		currentStatus, err := copystructure.Copy(getObjectStatus(o))
+	status, err := getObjectStatus(o)
+	if err != nil {
+		logger.Errorf("Error when get object status: %s", err.Error())
+		return res, errors.Wrap(err, ErrWhenGetObjectStatus.Error())
+	}
+	if status != nil {
+		currentStatus, err := copystructure.Copy(status)
 		if err != nil {
 			logger.Errorf("Error when get object status: %s", err.Error())
 			return res, errors.Wrap(err, ErrWhenGetObjectStatus.Error())
 		}
 		defer func() {
-			if !reflect.DeepEqual(currentStatus, getObjectStatus(o)) {
-				logger.Debugf("Detect that it need to update status with diff:\n%s", cmp.Diff(currentStatus, getObjectStatus(o)))
+			newStatus, _ := getObjectStatus(o)
+			if !reflect.DeepEqual(currentStatus, newStatus) {
+				logger.Debugf("Detect that it need to update status with diff:\n%s", cmp.Diff(currentStatus, newStatus))
 				if err = h.Client().Status().Update(ctx, o); err != nil {
 					logger.Errorf("Error when update resource status: %s", err.Error())
 				}
@@ -113,6 +134,29 @@ func (h *BasicRemoteReconciler[k8sObject, apiObject, apiClient]) Reconcile(ctx c
 		return res, nil
 	}
 
+	// Record the Secrets and ConfigMaps read during reconcile, to reconcile again when they change
+	ctx, registerDependencies := trackDependencies(ctx, h.Client(), h.dependencyRegistry, o, logger)
+	defer registerDependencies()
+
+	// Compute actions allowed by pause and maintenance window annotations
+	control, err := NewReconcileControl(o, time.Now())
+	if err != nil {
+		logger.Errorf("Error when compute reconcile control: %s", err.Error())
+		return reconciler.OnError(ctx, o, data, handler, NewTerminalError(errors.Wrap(err, ErrWhenComputeReconcileControl.Error())), logger)
+	}
+
+	// Manage the children on another cluster if needed
+	if targetAction, ok := reconciler.(TargetClusterReconcilerAction); ok {
+		var targetClient client.Client
+		if targetClient, err = targetAction.GetTargetClient(ctx, o, logger); err != nil {
+			logger.Errorf("Error when call 'getTargetClient' from reconciler: %s", err.Error())
+			return reconciler.OnError(ctx, o, data, handler, errors.Wrap(err, ErrWhenGetTargetClient.Error()), logger)
+		}
+		if targetClient != nil {
+			ctx = WithTargetClient(ctx, targetClient)
+		}
+	}
+
 	// Get the remote handler
 	handler, res, err = reconciler.GetRemoteHandler(ctx, req, o, logger)
 	if err != nil {
@@ -158,7 +202,7 @@ func (h *BasicRemoteReconciler[k8sObject, apiObject, apiClient]) Reconcile(ctx c
 	}
 
 	// Handle delete finalizer
-	if !getObjectMeta(o).DeletionTimestamp.IsZero() {
+	if !o.GetDeletionTimestamp().IsZero() {
 		if h.finalizer.String() != "" && controllerutil.ContainsFinalizer(o, h.finalizer.String()) {
 			if err = reconciler.Delete(ctx, o, data, handler, logger); err != nil {
 				logger.Errorf("Error when call 'delete' from reconciler: %s", err.Error())
@@ -187,7 +231,10 @@ func (h *BasicRemoteReconciler[k8sObject, apiObject, apiClient]) Reconcile(ctx c
 		return res, nil
 	}
 
-	if diff.NeedCreate() {
+	if diff.NeedCreate() && !control.CanCreate() {
+		logger.Infof("Skip 'create' from reconciler because of pause or maintenance window")
+		control.Block("create")
+	} else if diff.NeedCreate() {
 		res, err = reconciler.Create(ctx, o, data, handler, diff.GetObjectToCreate(), logger)
 		if err != nil {
 			logger.Errorf("Failed to call 'create' from reconciler: %s", err.Error())
@@ -199,7 +246,10 @@ func (h *BasicRemoteReconciler[k8sObject, apiObject, apiClient]) Reconcile(ctx c
 		}
 	}
 
-	if diff.NeedUpdate() {
+	if diff.NeedUpdate() && !control.CanUpdate() {
+		logger.Infof("Skip 'update' from reconciler because of pause or maintenance window")
+		control.Block("update")
+	} else if diff.NeedUpdate() {
 		res, err = reconciler.Update(ctx, o, data, handler, diff.GetObjectToUpdate(), logger)
 		if err != nil {
 			logger.Errorf("Failed to call 'update' from reconciler: %s", err.Error())
@@ -211,6 +261,13 @@ func (h *BasicRemoteReconciler[k8sObject, apiObject, apiClient]) Reconcile(ctx c
 		}
 	}
 
+	// Some actions are skipped by pause or maintenance window, wait to run them
+	control.SetCondition(NewBasicConditionManager(o, o.GetStatus()))
+	if control.IsBlocked() {
+		logger.Infof("Skip actions until pause or maintenance window allow them: %s", strings.Join(control.GetBlockedActions(), ", "))
+		return ctrl.Result{RequeueAfter: control.RequeueAfter()}, nil
+	}
+
 	res, err = reconciler.OnSuccess(ctx, o, data, handler, diff, logger)
 	if err != nil {
 		logger.Errorf("Error when call 'onSuccess' from reconciler: %s", err.Error())
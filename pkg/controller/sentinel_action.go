@@ -8,8 +8,8 @@ import (
 	"github.com/disaster37/k8s-objectmatcher/patch"
 	"github.com/disaster37/operator-sdk-extra/pkg/helper"
 	"github.com/sirupsen/logrus"
-	"github.com/thoas/go-funk"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/tools/record"
 	k8sstrings "k8s.io/utils/strings"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -54,18 +54,35 @@ type SentinelReconcilerAction interface {
 	GetIgnoresDiff() []patch.CalculateOption
 }
 
+// SentinelFinalizerAction is optional interface of sentinel actions to clean the objects generated from the sentinel object when it's deleted
+// The sentinel reconciler only add its finalizer on objects when the action implement it
+type SentinelFinalizerAction interface {
+
+	// Finalize permit to clean the objects generated from the sentinel object when it's deleted
+	// It only call if you have specified finalizer name when you create reconciler and if resource as marked to be deleted
+	Finalize(ctx context.Context, o client.Object, data map[string]any, read SentinelRead, logger *logrus.Entry) (err error)
+}
+
 // BasicSentinelAction is the basic implementation of SentinelAction
 type BasicSentinelAction struct {
 	BasicReconcilerAction
+	ownershipStrategy OwnershipStrategy
 }
 
 // NewRemoteReconcilerAction is the basic constructor of RemoteReconcilerAction interface
 func NewBasicSentinelAction(client client.Client, recorder record.EventRecorder) (sentinelReconciler SentinelReconcilerAction) {
 	return &BasicSentinelAction{
 		BasicReconcilerAction: NewBasicReconcilerAction(client, recorder, ReadyCondition),
+		ownershipStrategy:     NewBasicOwnershipStrategy(client),
 	}
 }
 
+// SetOwnershipStrategy permit to change the way to link the created objects to their parent
+// Default to BasicOwnershipStrategy
+func (h *BasicSentinelAction) SetOwnershipStrategy(strategy OwnershipStrategy) {
+	h.ownershipStrategy = strategy
+}
+
 func (h *BasicSentinelAction) Configure(ctx context.Context, req ctrl.Request, o client.Object, data map[string]any, logger *logrus.Entry) (res ctrl.Result, err error) {
 	return res, nil
 }
@@ -76,12 +93,16 @@ func (h *BasicSentinelAction) Read(ctx context.Context, o client.Object, data ma
 
 func (h *BasicSentinelAction) Create(ctx context.Context, o client.Object, data map[string]any, objects []client.Object, logger *logrus.Entry) (res ctrl.Result, err error) {
 
+	createdObjects := make([]client.Object, 0, len(objects))
+	defer func() {
+		h.recordObjectsEvent(o, "CreateCompleted", "created", createdObjects)
+	}()
+
 	for _, oChild := range objects {
 
 		// Set owner
-		err = ctrl.SetControllerReference(o, oChild, h.Client().Scheme())
-		if err != nil {
-			return res, errors.Wrapf(err, "Error when set owner reference on object '%s'", oChild.GetName())
+		if err = h.ownershipStrategy.SetOwner(o, oChild); err != nil {
+			return res, errors.Wrapf(err, "Error when set owner on object '%s'", oChild.GetName())
 		}
 
 		// Set diff 3-way annotations
@@ -93,7 +114,7 @@ func (h *BasicSentinelAction) Create(ctx context.Context, o client.Object, data
 			return res, errors.Wrapf(err, "Error when create object '%s'", oChild.GetName())
 		}
 		logger.Debugf("Create object '%s' successfully", oChild.GetName())
-		h.Recorder().Eventf(o, corev1.EventTypeNormal, "CreateCompleted", "Object '%s' successfully created", oChild.GetName())
+		createdObjects = append(createdObjects, oChild)
 	}
 
 	return res, nil
@@ -103,12 +124,17 @@ func (h *BasicSentinelAction) Create(ctx context.Context, o client.Object, data
 // It only add some log / events
 func (h *BasicSentinelAction) Update(ctx context.Context, o client.Object, data map[string]any, objects []client.Object, logger *logrus.Entry) (res ctrl.Result, err error) {
 
+	updatedObjects := make([]client.Object, 0, len(objects))
+	defer func() {
+		h.recordObjectsEvent(o, "UpdateCompleted", "updated", updatedObjects)
+	}()
+
 	for _, oChild := range objects {
 		if err = h.Client().Update(ctx, oChild); err != nil {
 			return res, errors.Wrapf(err, "Error when update object '%s'", oChild.GetName())
 		}
 		logger.Debugf("Update object '%s' successfully", oChild.GetName())
-		h.Recorder().Eventf(o, corev1.EventTypeNormal, "UpdateCompleted", "Object '%s' successfully updated", oChild.GetName())
+		updatedObjects = append(updatedObjects, oChild)
 	}
 
 	return res, nil
@@ -117,20 +143,53 @@ func (h *BasicSentinelAction) Update(ctx context.Context, o client.Object, data
 // Delete delete objects
 func (h *BasicSentinelAction) Delete(ctx context.Context, o client.Object, data map[string]any, objects []client.Object, logger *logrus.Entry) (err error) {
 
+	deletedObjects := make([]client.Object, 0, len(objects))
+	defer func() {
+		h.recordObjectsEvent(o, "DeleteCompleted", "deleted", deletedObjects)
+	}()
+
 	for _, oChild := range objects {
 		if err = h.Client().Delete(ctx, oChild); err != nil {
 			return errors.Wrapf(err, "Error when delete object '%s'", oChild.GetName())
 		}
 		logger.Debugf("Delete object '%s' successfully", oChild.GetName())
-		h.Recorder().Eventf(o, corev1.EventTypeNormal, "DeleteCompleted", "Object '%s' successfully deleted", oChild.GetName())
+		deletedObjects = append(deletedObjects, oChild)
+	}
+
+	return nil
+}
+
+// Finalize delete all current objects read
+func (h *BasicSentinelAction) Finalize(ctx context.Context, o client.Object, data map[string]any, read SentinelRead, logger *logrus.Entry) (err error) {
+
+	deletedObjects := make([]client.Object, 0)
+	defer func() {
+		h.recordObjectsEvent(o, "DeleteCompleted", "deleted", deletedObjects)
+	}()
+
+	for _, objects := range read.GetAllCurrentObjects() {
+		for _, oChild := range objects {
+			if err = h.Client().Delete(ctx, oChild); err != nil {
+				if k8serrors.IsNotFound(err) {
+					continue
+				}
+				return errors.Wrapf(err, "Error when delete object '%s'", oChild.GetName())
+			}
+			logger.Debugf("Delete object '%s' successfully", oChild.GetName())
+			deletedObjects = append(deletedObjects, oChild)
+		}
 	}
 
 	return nil
 }
 
 func (h *BasicSentinelAction) OnError(ctx context.Context, o client.Object, data map[string]any, currentErr error, logger *logrus.Entry) (res ctrl.Result, err error) {
-	h.Recorder().Event(o, corev1.EventTypeWarning, "SentinelActionError", k8sstrings.ShortenString(currentErr.Error(), ShortenError))
-	return res, currentErr
+	// Dependency not ready is requeued quietly
+	if !IsDependencyNotReadyError(currentErr) {
+		h.Recorder().Event(o, corev1.EventTypeWarning, "SentinelActionError", k8sstrings.ShortenString(currentErr.Error(), ShortenError))
+	}
+
+	return ResultFromError(currentErr)
 }
 
 func (h *BasicSentinelAction) OnSuccess(ctx context.Context, o client.Object, data map[string]any, diff SentinelDiff, logger *logrus.Entry) (res ctrl.Result, err error) {
@@ -152,8 +211,7 @@ func (h *BasicSentinelAction) Diff(ctx context.Context, o client.Object, read Se
 	toDelete := make([]client.Object, 0)
 
 	// Compare the expected and current objects type
-	objectTypes := funk.Uniq(funk.Union(funk.Keys(read.GetAllCurrentObjects()), funk.Keys(read.GetAllExpectedObjects())).([]string)).([]string)
-	for _, objectType := range objectTypes {
+	for _, objectType := range getSentinelObjectTypes(read) {
 		logger.Debugf("Start process object type '%s'", objectType)
 		tmpCurrentObjects := make([]client.Object, len(read.GetCurrentObjects(objectType)))
 		copy(tmpCurrentObjects, read.GetCurrentObjects(objectType))
@@ -166,7 +224,9 @@ func (h *BasicSentinelAction) Diff(ctx context.Context, o client.Object, read Se
 					isFound = true
 
 					// Copy TypeMeta to work with some ignore rules like IgnorePDBSelector()
-					MustInjectTypeMeta(currentObject, expectedObject)
+					if err = InjectTypeMeta(currentObject, expectedObject); err != nil {
+						return diff, res, errors.Wrapf(err, "Error when inject TypeMeta on object '%s'", expectedObject.GetName())
+					}
 					patchResult, err := patch.DefaultPatchMaker.Calculate(currentObject, expectedObject, patchOptions...)
 					if err != nil {
 						return diff, res, errors.Wrapf(err, "Error when diffing object '%s'", currentObject.GetName())
@@ -0,0 +1,383 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/disaster37/operator-sdk-extra/pkg/apis/shared"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// SentinelAnnotationsKey is the key on data map where the sentinel controller put the annotations matching the annotation prefix
+	SentinelAnnotationsKey string = "sentinelAnnotations"
+
+	// sentinelMatchKey is the key on data map where the sentinel controller put if the object match the selectors
+	sentinelMatchKey string = "sentinelMatch"
+)
+
+var (
+	// SentinelSelectorTimeout is the max duration to read the namespace of object when evaluate the predicate
+	SentinelSelectorTimeout = 10 * time.Second
+)
+
+// SentinelSelector permit to select the objects handled by sentinel controller
+// All selectors set must match
+type SentinelSelector struct {
+
+	// LabelSelector is the selector on object labels
+	LabelSelector *metav1.LabelSelector
+
+	// AnnotationPrefix is the prefix of annotations that object must have at least one, like "myoperator.domain.com/"
+	AnnotationPrefix string
+
+	// NamespaceSelector is the selector on labels of object namespace
+	// The client used to get namespace must have right to read them
+	NamespaceSelector *metav1.LabelSelector
+}
+
+// Validate permit to check the selectors are valid
+func (h SentinelSelector) Validate() (err error) {
+	if _, err = metav1.LabelSelectorAsSelector(h.LabelSelector); err != nil {
+		return errors.Wrap(err, "Invalid label selector")
+	}
+	if _, err = metav1.LabelSelectorAsSelector(h.NamespaceSelector); err != nil {
+		return errors.Wrap(err, "Invalid namespace selector")
+	}
+
+	return nil
+}
+
+// Matches permit to know if object match the selectors
+// The reader is used to get the namespace of object, use the cached client of manager to not call the API server
+func (h SentinelSelector) Matches(ctx context.Context, c client.Reader, o client.Object) (isMatch bool, err error) {
+	if h.LabelSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(h.LabelSelector)
+		if err != nil {
+			return false, errors.Wrap(err, "Invalid label selector")
+		}
+		if !selector.Matches(labels.Set(o.GetLabels())) {
+			return false, nil
+		}
+	}
+
+	if h.AnnotationPrefix != "" && len(h.ParseAnnotations(o)) == 0 {
+		return false, nil
+	}
+
+	// Cluster scoped object have no namespace to match
+	if h.NamespaceSelector != nil && o.GetNamespace() != "" {
+		selector, err := metav1.LabelSelectorAsSelector(h.NamespaceSelector)
+		if err != nil {
+			return false, errors.Wrap(err, "Invalid namespace selector")
+		}
+		namespace := &corev1.Namespace{}
+		if err = c.Get(ctx, types.NamespacedName{Name: o.GetNamespace()}, namespace); err != nil {
+			return false, errors.Wrapf(err, "Error when get namespace %s", o.GetNamespace())
+		}
+		if !selector.Matches(labels.Set(namespace.GetLabels())) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// ParseAnnotations permit to get the annotations matching the annotation prefix
+// The prefix is removed from the keys
+func (h SentinelSelector) ParseAnnotations(o client.Object) map[string]string {
+	annotations := map[string]string{}
+	if h.AnnotationPrefix == "" {
+		return annotations
+	}
+
+	for key, value := range o.GetAnnotations() {
+		if name, isFound := strings.CutPrefix(key, h.AnnotationPrefix); isFound && name != "" {
+			annotations[name] = value
+		}
+	}
+
+	return annotations
+}
+
+// Predicate permit to get the predicate to only watch the objects matching the selectors
+// On update, the event is kept if old or new object match, to be able to clean when object not match anymore
+// When the selectors can't be evaluated, the event is kept and the reconciler evaluate them again
+func (h SentinelSelector) Predicate(c client.Reader) predicate.Predicate {
+	matches := func(o client.Object) bool {
+		if o == nil {
+			return false
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), SentinelSelectorTimeout)
+		defer cancel()
+		isMatch, err := h.Matches(ctx, c, o)
+		if err != nil {
+			logrus.Warnf("Error when evaluate sentinel selectors on object %s/%s, keep the event: %s", o.GetNamespace(), o.GetName(), err.Error())
+			return true
+		}
+		return isMatch
+	}
+
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return matches(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return matches(e.ObjectOld) || matches(e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return matches(e.Object)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return matches(e.Object)
+		},
+	}
+}
+
+// GetSentinelAnnotations permit to get the annotations put on data map by the sentinel controller
+// It return empty map if not found
+func GetSentinelAnnotations(data map[string]any) map[string]string {
+	if annotations, ok := data[SentinelAnnotationsKey].(map[string]string); ok {
+		return annotations
+	}
+
+	return map[string]string{}
+}
+
+// SentinelControllerBuilder permit to build sentinel controller from declarative selectors
+type SentinelControllerBuilder struct {
+	name           string
+	object         client.Object
+	selector       SentinelSelector
+	client         client.Client
+	recorder       record.EventRecorder
+	logger         *logrus.Entry
+	action         SentinelReconcilerAction
+	finalizer      shared.FinalizerName
+	ownedTypes     []client.Object
+	reconcileDelay time.Duration
+}
+
+// NewSentinelControllerBuilder permit to start to build sentinel controller that watch the object type
+func NewSentinelControllerBuilder(name string, o client.Object) *SentinelControllerBuilder {
+	return &SentinelControllerBuilder{
+		name:           name,
+		object:         o,
+		ownedTypes:     make([]client.Object, 0),
+		reconcileDelay: DefaultReconcileDelay,
+	}
+}
+
+// WithClient permit to set the client
+func (h *SentinelControllerBuilder) WithClient(client client.Client) *SentinelControllerBuilder {
+	h.client = client
+	return h
+}
+
+// WithRecorder permit to set the event recorder
+func (h *SentinelControllerBuilder) WithRecorder(recorder record.EventRecorder) *SentinelControllerBuilder {
+	h.recorder = recorder
+	return h
+}
+
+// WithLogger permit to set the logger
+func (h *SentinelControllerBuilder) WithLogger(logger *logrus.Entry) *SentinelControllerBuilder {
+	h.logger = logger
+	return h
+}
+
+// WithAction permit to set the sentinel action
+func (h *SentinelControllerBuilder) WithAction(action SentinelReconcilerAction) *SentinelControllerBuilder {
+	h.action = action
+	return h
+}
+
+// WithFinalizer permit to add finalizer on objects to call 'Finalize' from action before they are deleted
+func (h *SentinelControllerBuilder) WithFinalizer(finalizer shared.FinalizerName) *SentinelControllerBuilder {
+	h.finalizer = finalizer
+	return h
+}
+
+// WithLabelSelector permit to only handle objects matching the label selector
+func (h *SentinelControllerBuilder) WithLabelSelector(selector *metav1.LabelSelector) *SentinelControllerBuilder {
+	h.selector.LabelSelector = selector
+	return h
+}
+
+// WithAnnotationPrefix permit to only handle objects that have at least one annotation with this prefix
+func (h *SentinelControllerBuilder) WithAnnotationPrefix(prefix string) *SentinelControllerBuilder {
+	h.selector.AnnotationPrefix = prefix
+	return h
+}
+
+// WithNamespaceSelector permit to only handle objects on namespaces matching the label selector
+func (h *SentinelControllerBuilder) WithNamespaceSelector(selector *metav1.LabelSelector) *SentinelControllerBuilder {
+	h.selector.NamespaceSelector = selector
+	return h
+}
+
+// WithReconcileDelay permit to set the delay waited at the beginning of each reconcile loop
+// Tests with fake client can set it to 0 to run reconcile loops without waiting
+func (h *SentinelControllerBuilder) WithReconcileDelay(delay time.Duration) *SentinelControllerBuilder {
+	h.reconcileDelay = delay
+	return h
+}
+
+// Owns permit to watch the objects created by the sentinel action
+func (h *SentinelControllerBuilder) Owns(objects ...client.Object) *SentinelControllerBuilder {
+	h.ownedTypes = append(h.ownedTypes, objects...)
+	return h
+}
+
+// Build permit to get the sentinel controller
+func (h *SentinelControllerBuilder) Build() (Controller, error) {
+	if h.name == "" {
+		return nil, errors.New("Name can't be empty")
+	}
+	if h.object == nil {
+		return nil, errors.New("Object can't be nil")
+	}
+	if h.client == nil {
+		return nil, errors.New("Client can't be nil, use WithClient")
+	}
+	if h.recorder == nil {
+		return nil, errors.New("Recorder can't be nil, use WithRecorder")
+	}
+	if h.action == nil {
+		return nil, errors.New("Action can't be nil, use WithAction")
+	}
+	if err := h.selector.Validate(); err != nil {
+		return nil, err
+	}
+
+	logger := h.logger
+	if logger == nil {
+		logger = logrus.NewEntry(logrus.StandardLogger())
+	}
+
+	reconciler := NewBasicSentinelReconcilerWithFinalizer(h.client, h.name, h.finalizer, logger, h.recorder)
+	reconciler.SetReconcileDelay(h.reconcileDelay)
+
+	return &BasicSentinelController{
+		name:       h.name,
+		object:     h.object,
+		selector:   h.selector,
+		client:     h.client,
+		ownedTypes: h.ownedTypes,
+		reconciler: reconciler,
+		action:     newSentinelSelectorAction(h.action, h.selector),
+	}, nil
+}
+
+// BasicSentinelController is the sentinel controller built by SentinelControllerBuilder
+type BasicSentinelController struct {
+	name       string
+	object     client.Object
+	selector   SentinelSelector
+	client     client.Client
+	ownedTypes []client.Object
+	reconciler SentinelReconciler
+	action     SentinelReconcilerAction
+}
+
+func (h *BasicSentinelController) Reconcile(ctx context.Context, req reconcile.Request) (res reconcile.Result, err error) {
+	return h.reconciler.Reconcile(ctx, req, h.object.DeepCopyObject().(client.Object), map[string]any{}, h.action)
+}
+
+// SetupWithManager permit to watch the objects matching the selectors and the owned objects
+// The owned objects are watched from owner reference and from tracking labels, to handle cross namespace and cluster scoped objects
+func (h *BasicSentinelController) SetupWithManager(mgr ctrl.Manager) error {
+	if err := ValidateStatusAccessors(h.object); err != nil {
+		return errors.Wrap(err, "Error when validate status accessor of watched object")
+	}
+
+	gvk, err := apiutil.GVKForObject(h.object, mgr.GetScheme())
+	if err != nil {
+		return errors.Wrap(err, "Error when get GVK of watched object")
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		Named(h.name).
+		For(h.object, builder.WithPredicates(h.selector.Predicate(mgr.GetClient())))
+
+	for _, ownedType := range h.ownedTypes {
+		b = b.Owns(ownedType).
+			Watches(ownedType, NewTrackedChildEventHandler(gvk.GroupKind()))
+	}
+
+	return b.Complete(h)
+}
+
+// sentinelSelectorAction wrap the sentinel action to put the annotations matching the prefix on data map
+// When the object not match the selectors anymore, it remove all expected objects so the generated objects are deleted
+type sentinelSelectorAction struct {
+	SentinelReconcilerAction
+	selector SentinelSelector
+}
+
+// sentinelSelectorFinalizerAction is the sentinelSelectorAction that keep the Finalize of the sentinel action
+type sentinelSelectorFinalizerAction struct {
+	*sentinelSelectorAction
+	finalizerAction SentinelFinalizerAction
+}
+
+// newSentinelSelectorAction permit to wrap the sentinel action, only implement SentinelFinalizerAction if the action implement it
+func newSentinelSelectorAction(action SentinelReconcilerAction, selector SentinelSelector) SentinelReconcilerAction {
+	selectorAction := &sentinelSelectorAction{
+		SentinelReconcilerAction: action,
+		selector:                 selector,
+	}
+	if finalizerAction, ok := action.(SentinelFinalizerAction); ok {
+		return &sentinelSelectorFinalizerAction{
+			sentinelSelectorAction: selectorAction,
+			finalizerAction:        finalizerAction,
+		}
+	}
+
+	return selectorAction
+}
+
+func (h *sentinelSelectorAction) Configure(ctx context.Context, req ctrl.Request, o client.Object, data map[string]any, logger *logrus.Entry) (res ctrl.Result, err error) {
+	isMatch, err := h.selector.Matches(ctx, h.Client(), o)
+	if err != nil {
+		return res, errors.Wrap(err, "Error when evaluate sentinel selectors")
+	}
+	data[sentinelMatchKey] = isMatch
+	data[SentinelAnnotationsKey] = h.selector.ParseAnnotations(o)
+
+	return h.SentinelReconcilerAction.Configure(ctx, req, o, data, logger)
+}
+
+func (h *sentinelSelectorAction) Read(ctx context.Context, o client.Object, data map[string]any, logger *logrus.Entry) (read SentinelRead, res ctrl.Result, err error) {
+	read, res, err = h.SentinelReconcilerAction.Read(ctx, o, data, logger)
+	if err != nil || read == nil {
+		return read, res, err
+	}
+
+	if isMatch, ok := data[sentinelMatchKey].(bool); ok && !isMatch {
+		logger.Info("Object not match the selectors anymore, clean the generated objects")
+		for objectType := range read.GetAllExpectedObjects() {
+			read.SetExpectedObjects(objectType, nil)
+		}
+	}
+
+	return read, res, nil
+}
+
+func (h *sentinelSelectorFinalizerAction) Finalize(ctx context.Context, o client.Object, data map[string]any, read SentinelRead, logger *logrus.Entry) (err error) {
+	return h.finalizerAction.Finalize(ctx, o, data, read, logger)
+}
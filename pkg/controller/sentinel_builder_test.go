@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestSentinelSelector(t *testing.T) {
+	fakeClient := fake.NewClientBuilder().WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod", Labels: map[string]string{"env": "prod"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "dev", Labels: map[string]string{"env": "dev"}}},
+	).Build()
+	selector := SentinelSelector{
+		LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+		AnnotationPrefix:  "test.domain.com/",
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+	}
+	assert.NoError(t, selector.Validate())
+
+	o := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "prod",
+			Labels:    map[string]string{"app": "test"},
+			Annotations: map[string]string{
+				"test.domain.com/foo": "bar",
+				"test.domain.com/":    "empty",
+				"other.domain.com/":   "other",
+			},
+		},
+	}
+
+	// When all selectors match
+	isMatch, err := selector.Matches(context.Background(), fakeClient, o)
+	assert.NoError(t, err)
+	assert.True(t, isMatch)
+	assert.Equal(t, map[string]string{"foo": "bar"}, selector.ParseAnnotations(o))
+
+	// When namespace not match
+	o.Namespace = "dev"
+	isMatch, err = selector.Matches(context.Background(), fakeClient, o)
+	assert.NoError(t, err)
+	assert.False(t, isMatch)
+
+	// When namespace not found
+	o.Namespace = "unknown"
+	_, err = selector.Matches(context.Background(), fakeClient, o)
+	assert.Error(t, err)
+
+	// When label not match
+	o.Namespace = "prod"
+	o.Labels = nil
+	isMatch, err = selector.Matches(context.Background(), fakeClient, o)
+	assert.NoError(t, err)
+	assert.False(t, isMatch)
+
+	// When annotation not match
+	o.Labels = map[string]string{"app": "test"}
+	o.Annotations = map[string]string{"other.domain.com/foo": "bar"}
+	isMatch, err = selector.Matches(context.Background(), fakeClient, o)
+	assert.NoError(t, err)
+	assert.False(t, isMatch)
+
+	// When invalid selector
+	selector.LabelSelector = &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "bad"}}}
+	assert.Error(t, selector.Validate())
+}
+
+func TestSentinelSelectorPredicate(t *testing.T) {
+	selector := SentinelSelector{
+		AnnotationPrefix: "test.domain.com/",
+	}
+	p := selector.Predicate(fake.NewClientBuilder().Build())
+
+	match := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test", Annotations: map[string]string{"test.domain.com/foo": "bar"}}}
+	notMatch := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+
+	assert.True(t, p.Create(event.CreateEvent{Object: match}))
+	assert.False(t, p.Create(event.CreateEvent{Object: notMatch}))
+	assert.True(t, p.Delete(event.DeleteEvent{Object: match}))
+
+	// When object not match anymore, keep it to clean
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: match, ObjectNew: notMatch}))
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: notMatch, ObjectNew: notMatch}))
+
+	// When selectors can't be evaluated, keep the event
+	selector.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}
+	p = selector.Predicate(fake.NewClientBuilder().Build())
+	match.Namespace = "unknown"
+	assert.True(t, p.Create(event.CreateEvent{Object: match}))
+}
+
+func TestSentinelControllerBuilder(t *testing.T) {
+	fakeClient := fake.NewClientBuilder().Build()
+	recorder := record.NewFakeRecorder(10)
+
+	// When missing action
+	_, err := NewSentinelControllerBuilder("test", &corev1.ConfigMap{}).
+		WithClient(fakeClient).
+		WithRecorder(recorder).
+		Build()
+	assert.Error(t, err)
+
+	// When invalid selector
+	_, err = NewSentinelControllerBuilder("test", &corev1.ConfigMap{}).
+		WithClient(fakeClient).
+		WithRecorder(recorder).
+		WithAction(NewBasicSentinelAction(fakeClient, recorder)).
+		WithLabelSelector(&metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "bad"}}}).
+		Build()
+	assert.Error(t, err)
+
+	// When all is right
+	c, err := NewSentinelControllerBuilder("test", &corev1.ConfigMap{}).
+		WithClient(fakeClient).
+		WithRecorder(recorder).
+		WithLogger(logrus.NewEntry(logrus.New())).
+		WithAction(NewBasicSentinelAction(fakeClient, recorder)).
+		WithAnnotationPrefix("test.domain.com/").
+		Owns(&corev1.Secret{}).
+		Build()
+	assert.NoError(t, err)
+	assert.NotNil(t, c)
+
+	// Configure put the parsed annotations on data
+	o := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test", Annotations: map[string]string{"test.domain.com/foo": "bar"}}}
+	data := map[string]any{}
+	_, err = c.(*BasicSentinelController).action.Configure(context.Background(), ctrl.Request{}, o, data, logrus.NewEntry(logrus.New()))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"foo": "bar"}, GetSentinelAnnotations(data))
+	assert.Empty(t, GetSentinelAnnotations(map[string]any{}))
+
+	_, isFinalizerAction := c.(*BasicSentinelController).action.(SentinelFinalizerAction)
+	assert.True(t, isFinalizerAction)
+
+	// When action not implement SentinelFinalizerAction
+	c, err = NewSentinelControllerBuilder("test", &corev1.ConfigMap{}).
+		WithClient(fakeClient).
+		WithRecorder(recorder).
+		WithAction(&testSentinelActionWithoutFinalizer{SentinelReconcilerAction: NewBasicSentinelAction(fakeClient, recorder)}).
+		Build()
+	assert.NoError(t, err)
+	_, isFinalizerAction = c.(*BasicSentinelController).action.(SentinelFinalizerAction)
+	assert.False(t, isFinalizerAction)
+}
+
+func TestSentinelSelectorActionWhenNotMatch(t *testing.T) {
+	fakeClient := fake.NewClientBuilder().WithObjects(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: "other"}},
+	).Build()
+	recorder := record.NewFakeRecorder(10)
+	action := newSentinelSelectorAction(&testFinalizeSentinelAction{
+		BasicSentinelAction: NewBasicSentinelAction(fakeClient, recorder).(*BasicSentinelAction),
+	}, SentinelSelector{AnnotationPrefix: "test.domain.com/"})
+	logger := logrus.NewEntry(logrus.New())
+
+	// When object match, expected objects are kept
+	o := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", Annotations: map[string]string{"test.domain.com/foo": "bar"}}}
+	data := map[string]any{}
+	_, err := action.Configure(context.Background(), ctrl.Request{}, o, data, logger)
+	assert.NoError(t, err)
+	read, _, err := action.Read(context.Background(), o, data, logger)
+	assert.NoError(t, err)
+	assert.Len(t, read.GetExpectedObjects("secret"), 1)
+
+	// When object not match anymore, expected objects are removed to clean them
+	o.Annotations = nil
+	data = map[string]any{}
+	_, err = action.Configure(context.Background(), ctrl.Request{}, o, data, logger)
+	assert.NoError(t, err)
+	read, _, err = action.Read(context.Background(), o, data, logger)
+	assert.NoError(t, err)
+	assert.Empty(t, read.GetExpectedObjects("secret"))
+	diff, _, err := action.Diff(context.Background(), o, read, data, logger)
+	assert.NoError(t, err)
+	assert.Len(t, diff.GetObjectsToDelete(), 1)
+}
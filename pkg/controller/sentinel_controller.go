@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"emperror.dev/errors"
+	"github.com/disaster37/operator-sdk-extra/pkg/apis/shared"
 	"github.com/google/go-cmp/cmp"
 	"github.com/mitchellh/copystructure"
 	"github.com/sirupsen/logrus"
@@ -14,6 +15,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 // SentinelReconciler must be used when you look resource that your operator is not the owner like ingress, secret, configMap, etc.
@@ -23,6 +25,12 @@ type SentinelReconciler interface {
 
 	// Reconcile permit to orchestrate all phase needed to successfully reconcile the object
 	Reconcile(ctx context.Context, req ctrl.Request, o client.Object, data map[string]interface{}, reconciler SentinelReconcilerAction) (res ctrl.Result, err error)
+
+	// SetReconcileDelay permit to set the delay waited at the beginning of each reconcile loop
+	SetReconcileDelay(delay time.Duration)
+
+	// DependencyRegistry permit to get the registry of dependencies read during reconcile, to watch them with SetupTrackedDependenciesWithManager
+	DependencyRegistry() *DependencyRegistry
 }
 
 // BasicSentinelReconciler is the basic sentinel reconsiler
@@ -31,13 +39,21 @@ type BasicSentinelReconciler struct {
 }
 
 // NewBasicSentinelReconciler permit to instanciate new basic sentinel resonciler
+// The objects generated are cleaned by the garbage collector from owner references
 func NewBasicSentinelReconciler(client client.Client, name string, logger *logrus.Entry, recorder record.EventRecorder) (sentinelReconciler SentinelReconciler) {
+	return NewBasicSentinelReconcilerWithFinalizer(client, name, "", logger, recorder)
+}
+
+// NewBasicSentinelReconcilerWithFinalizer permit to instanciate new basic sentinel resonciler with finalizer
+// Use it when the objects generated can't be cleaned by the garbage collector, like objects on other namespaces or on remote systems
+// The finalizer is only added when the action implement SentinelFinalizerAction. On deletion, it call 'Finalize' from action before to remove the finalizer
+func NewBasicSentinelReconcilerWithFinalizer(client client.Client, name string, finalizer shared.FinalizerName, logger *logrus.Entry, recorder record.EventRecorder) (sentinelReconciler SentinelReconciler) {
 
 	return &BasicSentinelReconciler{
 		BasicReconciler: NewBasicReconciler(
 			client,
 			recorder,
-			"",
+			finalizer,
 			logger.WithFields(logrus.Fields{
 				"reconciler": name,
 			}),
@@ -45,8 +61,7 @@ func NewBasicSentinelReconciler(client client.Client, name string, logger *logru
 	}
 }
 
-// No need to add finalizer and manage delete
-// All sub resources must be children of main parent. So the clean is handled by kubelet in lazy effort
+// When no finalizer is set, the sub resources must be children of main parent. So the clean is handled by kubelet in lazy effort
 func (h *BasicSentinelReconciler) Reconcile(ctx context.Context, req ctrl.Request, o client.Object, data map[string]interface{}, reconcilerAction SentinelReconcilerAction) (res ctrl.Result, err error) {
 
 	var (
@@ -62,12 +77,20 @@ func (h *BasicSentinelReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	logger.Infof("Starting reconcile loop")
 	defer logger.Info("Finish reconcile loop")
 
+	// Handle terminal, transient and dependency errors when OnError not already do it
+	defer func() {
+		if err != nil {
+			res, err = ResultFromError(err)
+		}
+	}()
+
 	// Wait few second to be sure status is propaged througout ETCD
-	time.Sleep(time.Second * 1)
+	time.Sleep(h.reconcileDelay)
 
 	// Get current resource
 	if err = h.Client().Get(ctx, req.NamespacedName, o); err != nil {
 		if k8serrors.IsNotFound(err) {
+			untrackDependencies(h.Client(), h.dependencyRegistry, o, req.NamespacedName)
 			return res, nil
 		}
 		logger.Errorf("Error when get object: %s", err.Error())
@@ -75,16 +98,36 @@ func (h *BasicSentinelReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 	logger.Debug("Get object successfully")
 
+	// Add finalizer only if the action can clean the generated objects
+	finalizerAction, isFinalizerAction := reconcilerAction.(SentinelFinalizerAction)
+	if h.finalizer != "" && isFinalizerAction && o.GetDeletionTimestamp().IsZero() {
+		if !controllerutil.ContainsFinalizer(o, h.finalizer.String()) {
+			controllerutil.AddFinalizer(o, h.finalizer.String())
+			if err = h.Client().Update(ctx, o); err != nil {
+				logger.Errorf("Error when add finalizer: %s", err.Error())
+				return reconcilerAction.OnError(ctx, o, data, errors.Wrap(err, ErrWhenAddFinalizer.Error()), logger)
+			}
+			logger.Debug("Add finalizer successfully, force requeue object")
+			return ctrl.Result{Requeue: true}, nil
+		}
+	}
+
 	// Handle status update if exist
-	if getObjectStatus(o) != nil {
-		currentStatus, err := copystructure.Copy(getObjectStatus(o))
+	status, err := getObjectStatus(o)
+	if err != nil {
+		logger.Errorf("Error when get object status: %s", err.Error())
+		return res, errors.Wrap(err, ErrWhenGetObjectStatus.Error())
+	}
+	if status != nil {
+		currentStatus, err := copystructure.Copy(status)
 		if err != nil {
 			logger.Errorf("Error when get object status: %s", err.Error())
 			return res, errors.Wrap(err, ErrWhenGetObjectStatus.Error())
 		}
 		defer func() {
-			if !reflect.DeepEqual(currentStatus, getObjectStatus(o)) {
-				logger.Debugf("Detect that it need to update status with diff:\n%s", cmp.Diff(currentStatus, getObjectStatus(o)))
+			newStatus, _ := getObjectStatus(o)
+			if !reflect.DeepEqual(currentStatus, newStatus) {
+				logger.Debugf("Detect that it need to update status with diff:\n%s", cmp.Diff(currentStatus, newStatus))
 				if err = h.Client().Status().Update(ctx, o); err != nil {
 					logger.Errorf("Error when update resource status: %s", err.Error())
 				}
@@ -99,6 +142,10 @@ func (h *BasicSentinelReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return res, nil
 	}
 
+	// Record the Secrets and ConfigMaps read during reconcile, to reconcile again when they change
+	ctx, registerDependencies := trackDependencies(ctx, h.Client(), h.dependencyRegistry, o, logger)
+	defer registerDependencies()
+
 	// Configure to optional get driver client (call meta)
 	res, err = reconcilerAction.Configure(ctx, req, o, data, logger)
 	if err != nil {
@@ -121,6 +168,27 @@ func (h *BasicSentinelReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return res, nil
 	}
 
+	// Handle delete finalizer
+	if !o.GetDeletionTimestamp().IsZero() {
+		if h.finalizer.String() != "" && controllerutil.ContainsFinalizer(o, h.finalizer.String()) {
+			if isFinalizerAction {
+				if err = finalizerAction.Finalize(ctx, o, data, read, logger); err != nil {
+					logger.Errorf("Error when call 'finalize' from reconciler: %s", err.Error())
+					return reconcilerAction.OnError(ctx, o, data, errors.Wrap(err, ErrWhenCallFinalizeFromReconciler.Error()), logger)
+				}
+				logger.Debug("Call 'finalize' from reconciler successfully")
+			}
+
+			controllerutil.RemoveFinalizer(o, h.finalizer.String())
+			if err = h.Client().Update(ctx, o); err != nil {
+				logger.Errorf("Failed to remove finalizer: %s", err.Error())
+				return reconcilerAction.OnError(ctx, o, data, errors.Wrap(err, ErrWhenDeleteFinalizer.Error()), logger)
+			}
+			logger.Debug("Remove finalizer successfully")
+		}
+		return ctrl.Result{}, nil
+	}
+
 	// Check if diff exist
 	diff, res, err = reconcilerAction.Diff(ctx, o, read, data, logger, reconcilerAction.GetIgnoresDiff()...)
 	if err != nil {
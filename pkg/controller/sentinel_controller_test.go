@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+type testFinalizeSentinelAction struct {
+	*BasicSentinelAction
+}
+
+func (h *testFinalizeSentinelAction) Read(ctx context.Context, o client.Object, data map[string]any, logger *logrus.Entry) (read SentinelRead, res ctrl.Result, err error) {
+	read = NewBasicSentinelRead()
+
+	secrets := &corev1.SecretList{}
+	if err = h.Client().List(ctx, secrets, client.InNamespace("other")); err != nil {
+		return nil, res, err
+	}
+	objects := make([]client.Object, 0, len(secrets.Items))
+	for _, secret := range secrets.Items {
+		objects = append(objects, &secret)
+	}
+	read.SetCurrentObjects("secret", objects)
+	read.SetExpectedObjects("secret", objects)
+
+	return read, res, nil
+}
+
+// testSentinelActionWithoutFinalizer hide the Finalize from embedded action
+type testSentinelActionWithoutFinalizer struct {
+	SentinelReconcilerAction
+}
+
+func TestBasicSentinelReconcilerFinalizer(t *testing.T) {
+	o := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	fakeClient := fake.NewClientBuilder().WithObjects(
+		o,
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: "other"}},
+	).Build()
+	recorder := record.NewFakeRecorder(10)
+	reconciler := NewBasicSentinelReconcilerWithFinalizer(fakeClient, "test", "test.domain.com/finalizer", logrus.NewEntry(logrus.New()), recorder)
+	action := &testFinalizeSentinelAction{
+		BasicSentinelAction: NewBasicSentinelAction(fakeClient, recorder).(*BasicSentinelAction),
+	}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(o)}
+
+	// When action not implement SentinelFinalizerAction
+	_, err := reconciler.Reconcile(context.Background(), req, &corev1.ConfigMap{}, map[string]any{}, &testSentinelActionWithoutFinalizer{SentinelReconcilerAction: action})
+	assert.NoError(t, err)
+	assert.NoError(t, fakeClient.Get(context.Background(), req.NamespacedName, o))
+	assert.False(t, controllerutil.ContainsFinalizer(o, "test.domain.com/finalizer"))
+
+	// When add finalizer
+	res, err := reconciler.Reconcile(context.Background(), req, &corev1.ConfigMap{}, map[string]any{}, action)
+	assert.NoError(t, err)
+	assert.True(t, res.Requeue)
+	assert.NoError(t, fakeClient.Get(context.Background(), req.NamespacedName, o))
+	assert.True(t, controllerutil.ContainsFinalizer(o, "test.domain.com/finalizer"))
+
+	// When delete, finalize and remove finalizer
+	assert.NoError(t, fakeClient.Delete(context.Background(), o))
+	_, err = reconciler.Reconcile(context.Background(), req, &corev1.ConfigMap{}, map[string]any{}, action)
+	assert.NoError(t, err)
+	assert.True(t, k8serrors.IsNotFound(fakeClient.Get(context.Background(), req.NamespacedName, &corev1.ConfigMap{})))
+	assert.True(t, k8serrors.IsNotFound(fakeClient.Get(context.Background(), client.ObjectKey{Name: "child", Namespace: "other"}, &corev1.Secret{})))
+	if assert.Len(t, recorder.Events, 1) {
+		assert.Equal(t, "Normal DeleteCompleted Object 'child' successfully deleted", <-recorder.Events)
+	}
+}
+
+func TestBasicSentinelActionFinalize(t *testing.T) {
+	o := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	fakeClient := fake.NewClientBuilder().WithObjects(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: "other"}},
+	).Build()
+	recorder := record.NewFakeRecorder(10)
+	action := NewBasicSentinelAction(fakeClient, recorder).(SentinelFinalizerAction)
+
+	// When some objects are already deleted
+	read := NewBasicSentinelRead()
+	read.SetCurrentObjects("secret", []client.Object{
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: "other"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "deleted", Namespace: "other"}},
+	})
+	assert.NoError(t, action.Finalize(context.Background(), o, map[string]any{}, read, logrus.NewEntry(logrus.New())))
+	assert.True(t, k8serrors.IsNotFound(fakeClient.Get(context.Background(), client.ObjectKey{Name: "child", Namespace: "other"}, &corev1.Secret{})))
+	assert.Len(t, recorder.Events, 1)
+}
@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"emperror.dev/errors"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// TypedSentinelRead is the SentinelRead where objects are grouped by GroupVersionKind
+// The objects are also available from SentinelRead interface with gvk.String() as object type
+type TypedSentinelRead interface {
+	SentinelRead
+
+	// GetAllCurrentObjectsByGVK permit to get the map of current objects, mapped by GVK
+	GetAllCurrentObjectsByGVK() map[schema.GroupVersionKind][]client.Object
+
+	// GetCurrentObjectsByGVK permit to get the list of current objects of GVK
+	GetCurrentObjectsByGVK(gvk schema.GroupVersionKind) []client.Object
+
+	// SetCurrentObjectsByGVK permit to set the list of current objects of GVK
+	// It return error if one object is not of this GVK
+	SetCurrentObjectsByGVK(gvk schema.GroupVersionKind, objects []client.Object) (err error)
+
+	// GetAllExpectedObjectsByGVK permit to get the map of expected objects, mapped by GVK
+	GetAllExpectedObjectsByGVK() map[schema.GroupVersionKind][]client.Object
+
+	// GetExpectedObjectsByGVK permit to get the list of expected objects of GVK
+	GetExpectedObjectsByGVK(gvk schema.GroupVersionKind) []client.Object
+
+	// SetExpectedObjectsByGVK permit to set the list of expected objects of GVK
+	// It return error if one object is not of this GVK
+	SetExpectedObjectsByGVK(gvk schema.GroupVersionKind, objects []client.Object) (err error)
+}
+
+// BasicTypedSentinelRead is the basic implementation of TypedSentinelRead
+type BasicTypedSentinelRead struct {
+	SentinelRead
+	scheme *runtime.Scheme
+	gvks   map[string]schema.GroupVersionKind
+}
+
+// NewBasicTypedSentinelRead is the basic constructor of TypedSentinelRead interface
+// The scheme is used to check the GVK of objects
+func NewBasicTypedSentinelRead(scheme *runtime.Scheme) TypedSentinelRead {
+	if scheme == nil {
+		panic("scheme can't be nil")
+	}
+
+	return &BasicTypedSentinelRead{
+		SentinelRead: NewBasicSentinelRead(),
+		scheme:       scheme,
+		gvks:         map[string]schema.GroupVersionKind{},
+	}
+}
+
+func (h *BasicTypedSentinelRead) GetAllCurrentObjectsByGVK() map[schema.GroupVersionKind][]client.Object {
+	return h.byGVK(h.GetAllCurrentObjects())
+}
+
+func (h *BasicTypedSentinelRead) GetCurrentObjectsByGVK(gvk schema.GroupVersionKind) []client.Object {
+	return h.GetCurrentObjects(gvk.String())
+}
+
+func (h *BasicTypedSentinelRead) SetCurrentObjectsByGVK(gvk schema.GroupVersionKind, objects []client.Object) (err error) {
+	if err = h.checkGVK(gvk, objects); err != nil {
+		return err
+	}
+	h.gvks[gvk.String()] = gvk
+	h.SetCurrentObjects(gvk.String(), objects)
+
+	return nil
+}
+
+func (h *BasicTypedSentinelRead) GetAllExpectedObjectsByGVK() map[schema.GroupVersionKind][]client.Object {
+	return h.byGVK(h.GetAllExpectedObjects())
+}
+
+func (h *BasicTypedSentinelRead) GetExpectedObjectsByGVK(gvk schema.GroupVersionKind) []client.Object {
+	return h.GetExpectedObjects(gvk.String())
+}
+
+func (h *BasicTypedSentinelRead) SetExpectedObjectsByGVK(gvk schema.GroupVersionKind, objects []client.Object) (err error) {
+	if err = h.checkGVK(gvk, objects); err != nil {
+		return err
+	}
+	h.gvks[gvk.String()] = gvk
+	h.SetExpectedObjects(gvk.String(), objects)
+
+	return nil
+}
+
+// checkGVK permit to check all objects are of GVK
+func (h *BasicTypedSentinelRead) checkGVK(gvk schema.GroupVersionKind, objects []client.Object) (err error) {
+	for _, o := range objects {
+		objectGVK, err := apiutil.GVKForObject(o, h.scheme)
+		if err != nil {
+			return errors.Wrapf(err, "Error when get GVK of object '%s'", o.GetName())
+		}
+		if objectGVK != gvk {
+			return errors.Errorf("Object '%s' is of GVK '%s' instead of '%s'", o.GetName(), objectGVK.String(), gvk.String())
+		}
+	}
+
+	return nil
+}
+
+// byGVK permit to convert the map keyed by object type to map keyed by GVK
+// The object types not set from GVK are ignored
+func (h *BasicTypedSentinelRead) byGVK(objectsByType map[string][]client.Object) map[schema.GroupVersionKind][]client.Object {
+	objectsByGVK := make(map[schema.GroupVersionKind][]client.Object, len(objectsByType))
+	for objectType, objects := range objectsByType {
+		if gvk, isFound := h.gvks[objectType]; isFound {
+			objectsByGVK[gvk] = objects
+		}
+	}
+
+	return objectsByGVK
+}
+
+// ListOwnedObjectsByGVK permit to list the objects of GVK generated from the sentinel object o
+// Only objects controlled by o or tracked with ParentUIDLabel are returned. Use opts to filter them, like with label selector.
+func ListOwnedObjectsByGVK(ctx context.Context, c client.Client, o client.Object, gvk schema.GroupVersionKind, opts ...client.ListOption) (objects []client.Object, err error) {
+	listObject, err := c.Scheme().New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error when create list of GVK '%s'", gvk.String())
+	}
+	list, ok := listObject.(client.ObjectList)
+	if !ok {
+		return nil, errors.Errorf("Type %T is not client.ObjectList", listObject)
+	}
+
+	if err = c.List(ctx, list, opts...); err != nil {
+		return nil, errors.Wrapf(err, "Error when list objects of GVK '%s'", gvk.String())
+	}
+
+	items := reflect.ValueOf(list).Elem().FieldByName("Items")
+	if !items.IsValid() {
+		return nil, errors.Errorf("List of GVK '%s' have no field Items", gvk.String())
+	}
+
+	objects = make([]client.Object, 0, items.Len())
+	for _, item := range helper.ToSliceOfObject(items.Interface()) {
+		if metav1.IsControlledBy(item, o) || (o.GetUID() != "" && item.GetLabels()[ParentUIDLabel] == string(o.GetUID())) {
+			objects = append(objects, item)
+		}
+	}
+
+	return objects, nil
+}
+
+// getSentinelObjectTypes permit to get the sorted union of current and expected object types
+func getSentinelObjectTypes(read SentinelRead) []string {
+	objectTypesSet := map[string]struct{}{}
+	for objectType := range read.GetAllCurrentObjects() {
+		objectTypesSet[objectType] = struct{}{}
+	}
+	for objectType := range read.GetAllExpectedObjects() {
+		objectTypesSet[objectType] = struct{}{}
+	}
+
+	objectTypes := make([]string, 0, len(objectTypesSet))
+	for objectType := range objectTypesSet {
+		objectTypes = append(objectTypes, objectType)
+	}
+	sort.Strings(objectTypes)
+
+	return objectTypes
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBasicTypedSentinelRead(t *testing.T) {
+	read := NewBasicTypedSentinelRead(scheme.Scheme)
+	secretGVK := corev1.SchemeGroupVersion.WithKind("Secret")
+	configMapGVK := corev1.SchemeGroupVersion.WithKind("ConfigMap")
+
+	// When set objects of right GVK
+	secrets := []client.Object{&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret"}}}
+	assert.NoError(t, read.SetCurrentObjectsByGVK(secretGVK, secrets))
+	assert.NoError(t, read.SetExpectedObjectsByGVK(configMapGVK, []client.Object{&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}}}))
+	assert.Equal(t, secrets, read.GetCurrentObjectsByGVK(secretGVK))
+	assert.Equal(t, secrets, read.GetCurrentObjects(secretGVK.String()))
+	assert.Len(t, read.GetAllCurrentObjectsByGVK(), 1)
+	assert.Len(t, read.GetExpectedObjectsByGVK(configMapGVK), 1)
+	assert.Contains(t, read.GetAllExpectedObjectsByGVK(), configMapGVK)
+
+	// When object not match its bucket
+	assert.Error(t, read.SetExpectedObjectsByGVK(secretGVK, []client.Object{&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}}}))
+	assert.Empty(t, read.GetExpectedObjectsByGVK(secretGVK))
+
+	// When object types are set from string, they are ignored from GVK view
+	read.SetCurrentObjects("other", secrets)
+	assert.Len(t, read.GetAllCurrentObjectsByGVK(), 1)
+	assert.Equal(t, []string{"/v1, Kind=ConfigMap", "/v1, Kind=Secret", "other"}, getSentinelObjectTypes(read))
+}
+
+func TestListOwnedObjectsByGVK(t *testing.T) {
+	o := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default", UID: "uid"}}
+	isController := true
+	fakeClient := fake.NewClientBuilder().WithObjects(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "owned", Namespace: "default", Labels: map[string]string{"app": "test"}, OwnerReferences: []metav1.OwnerReference{{Kind: "ConfigMap", APIVersion: "v1", Name: "parent", UID: "uid", Controller: &isController}}}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "tracked", Namespace: "other", Labels: map[string]string{"app": "test", ParentUIDLabel: "uid"}}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "not-owned", Namespace: "default", Labels: map[string]string{"app": "test"}}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other-label", Namespace: "other", Labels: map[string]string{ParentUIDLabel: "uid"}}},
+	).Build()
+
+	objects, err := ListOwnedObjectsByGVK(context.Background(), fakeClient, o, corev1.SchemeGroupVersion.WithKind("Secret"), client.MatchingLabels{"app": "test"})
+	assert.NoError(t, err)
+	names := make([]string, 0, len(objects))
+	for _, object := range objects {
+		names = append(names, object.GetName())
+	}
+	assert.ElementsMatch(t, []string{"owned", "tracked"}, names)
+
+	// When GVK is unknown
+	_, err = ListOwnedObjectsByGVK(context.Background(), fakeClient, o, corev1.SchemeGroupVersion.WithKind("Unknown"))
+	assert.Error(t, err)
+}
+
+func TestBasicSentinelActionDiffWithTypedRead(t *testing.T) {
+	o := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "default"}}
+	fakeClient := fake.NewClientBuilder().Build()
+	action := NewBasicSentinelAction(fakeClient, record.NewFakeRecorder(10))
+	read := NewBasicTypedSentinelRead(fakeClient.Scheme())
+	secretGVK := corev1.SchemeGroupVersion.WithKind("Secret")
+
+	assert.NoError(t, read.SetCurrentObjectsByGVK(secretGVK, []client.Object{&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "to-delete"}}}))
+	assert.NoError(t, read.SetExpectedObjectsByGVK(secretGVK, []client.Object{&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "to-create"}}}))
+	assert.NoError(t, read.SetExpectedObjectsByGVK(corev1.SchemeGroupVersion.WithKind("ConfigMap"), []client.Object{&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "to-create"}}}))
+
+	diff, _, err := action.Diff(context.Background(), o, read, map[string]any{}, logrus.NewEntry(logrus.New()))
+	assert.NoError(t, err)
+	assert.Len(t, diff.GetObjectsToCreate(), 2)
+	assert.Len(t, diff.GetObjectsToDelete(), 1)
+	assert.Empty(t, diff.GetObjectsToUpdate())
+}
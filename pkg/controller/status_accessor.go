@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"reflect"
+	"sync"
+
+	"emperror.dev/errors"
+	"github.com/mitchellh/copystructure"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ObjectStatusGetter can be implemented by objects to return their whole status without reflection
+// It can be generated alongside deepcopy
+type ObjectStatusGetter interface {
+	// GetObjectStatus permit to get the whole status of object
+	GetObjectStatus() any
+}
+
+// StatusAccessor permit to get the whole status of object
+// It need to return nil status if the object have no status
+type StatusAccessor func(o client.Object) (status any, err error)
+
+var (
+	statusAccessors     = map[reflect.Type]StatusAccessor{}
+	statusAccessorsLock sync.RWMutex
+)
+
+// RegisterStatusAccessor permit to register the status accessor of object type
+// It override the previous status accessor of the same type
+func RegisterStatusAccessor(o client.Object, accessor StatusAccessor) {
+	if o == nil {
+		panic("object can't be nil")
+	}
+	if accessor == nil {
+		panic("accessor can't be nil")
+	}
+
+	statusAccessorsLock.Lock()
+	defer statusAccessorsLock.Unlock()
+	statusAccessors[reflect.TypeOf(o)] = accessor
+}
+
+// getStatusAccessor permit to get the status accessor registered for the object type
+func getStatusAccessor(o client.Object) (accessor StatusAccessor, isFound bool) {
+	statusAccessorsLock.RLock()
+	defer statusAccessorsLock.RUnlock()
+	accessor, isFound = statusAccessors[reflect.TypeOf(o)]
+
+	return accessor, isFound
+}
+
+// getObjectStatus permit to get the status of object to detect when it need to be updated
+// It try in order the ObjectStatusGetter interface, the registered status accessor, the unstructured content and the field Status
+// It return nil status if the object have no status, and error if the object can't be read
+func getObjectStatus(o client.Object) (status any, err error) {
+	if isNilObject(o) {
+		return nil, errors.New("Object can't be nil")
+	}
+
+	if getter, ok := o.(ObjectStatusGetter); ok {
+		return getter.GetObjectStatus(), nil
+	}
+
+	if accessor, isFound := getStatusAccessor(o); isFound {
+		return accessor(o)
+	}
+
+	// For unstructured object, it return the status content or empty map if not yet exist
+	if u, ok := o.(runtime.Unstructured); ok {
+		if status, isFound := u.UnstructuredContent()["status"]; isFound && status != nil {
+			return status, nil
+		}
+		return map[string]any{}, nil
+	}
+
+	rv := reflect.ValueOf(o)
+	if rv.Kind() != reflect.Ptr {
+		return nil, errors.Errorf("Object of type %T must be a pointer", o)
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		return nil, errors.Errorf("Object of type %T must be a pointer of struct, or register a status accessor with RegisterStatusAccessor", o)
+	}
+	field := rv.FieldByName("Status")
+	if !field.IsValid() {
+		return nil, nil
+	}
+
+	return field.Interface(), nil
+}
+
+// ValidateStatusAccessors permit to check up-front that the status of objects can be read and copied
+// Call it when setup the controller to not fail on reconcile
+func ValidateStatusAccessors(objects ...client.Object) (err error) {
+	errs := make([]error, 0, len(objects))
+	for _, o := range objects {
+		status, err := getObjectStatus(o)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "Error when get status of type %T", o))
+			continue
+		}
+		if status == nil {
+			continue
+		}
+		if _, err = copystructure.Copy(status); err != nil {
+			errs = append(errs, errors.Wrapf(err, "Error when copy status of type %T", o))
+		}
+	}
+
+	return errors.Combine(errs...)
+}
+
+// isNilObject permit to check if object is nil, including typed nil pointer
+func isNilObject(o client.Object) bool {
+	if o == nil {
+		return true
+	}
+	rv := reflect.ValueOf(o)
+
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
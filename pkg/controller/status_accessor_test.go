@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/disaster37/operator-sdk-extra/pkg/apis"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type testStatusGetterObject struct {
+	corev1.ConfigMap
+}
+
+func (h *testStatusGetterObject) GetObjectStatus() any {
+	return h.Data
+}
+
+type testRegisteredStatusObject struct {
+	corev1.Secret
+}
+
+func TestGetObjectStatus(t *testing.T) {
+	// When typed object
+	status, err := getObjectStatus(&corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning}})
+	assert.NoError(t, err)
+	assert.Equal(t, corev1.PodStatus{Phase: corev1.PodRunning}, status)
+
+	// When object without status
+	status, err = getObjectStatus(&corev1.ConfigMap{})
+	assert.NoError(t, err)
+	assert.Nil(t, status)
+
+	// When object implement ObjectStatusGetter
+	status, err = getObjectStatus(&testStatusGetterObject{ConfigMap: corev1.ConfigMap{Data: map[string]string{"foo": "bar"}}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"foo": "bar"}, status)
+
+	// When status accessor is registered
+	RegisterStatusAccessor(&testRegisteredStatusObject{}, func(o client.Object) (status any, err error) {
+		return o.(*testRegisteredStatusObject).Type, nil
+	})
+	status, err = getObjectStatus(&testRegisteredStatusObject{Secret: corev1.Secret{Type: corev1.SecretTypeOpaque}})
+	assert.NoError(t, err)
+	assert.Equal(t, corev1.SecretTypeOpaque, status)
+
+	// When object is nil
+	var pod *corev1.Pod
+	_, err = getObjectStatus(pod)
+	assert.Error(t, err)
+}
+
+func TestGetObjectStatusWithUnstructured(t *testing.T) {
+	o := apis.NewUnstructuredMultiPhaseObject(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Test"})
+
+	// When status not yet exist
+	status, err := getObjectStatus(o)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{}, status)
+
+	// When status exist
+	o.GetStatus().SetPhaseName("running")
+	status, err = getObjectStatus(o)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"phase": "running"}, status)
+}
+
+func TestValidateStatusAccessors(t *testing.T) {
+	// When all objects are valid
+	assert.NoError(t, ValidateStatusAccessors(&corev1.Pod{}, &corev1.ConfigMap{}, &unstructured.Unstructured{}))
+
+	// When accessor return error
+	RegisterStatusAccessor(&testRegisteredStatusObject{}, func(o client.Object) (status any, err error) {
+		return nil, errors.New("test")
+	})
+	var pod *corev1.Pod
+	err := ValidateStatusAccessors(&corev1.Pod{}, &testRegisteredStatusObject{}, pod)
+	assert.Error(t, err)
+	assert.Len(t, errors.GetErrors(err), 2)
+}
+
+func TestInjectTypeMeta(t *testing.T) {
+	// When typed object
+	srcPod := &corev1.Pod{TypeMeta: metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"}}
+	dstPod := &corev1.Pod{}
+	assert.NoError(t, InjectTypeMeta(srcPod, dstPod))
+	assert.Equal(t, srcPod.TypeMeta, dstPod.TypeMeta)
+
+	// When object is nil
+	var pod *corev1.Pod
+	assert.Error(t, InjectTypeMeta(srcPod, pod))
+	assert.Error(t, InjectTypeMeta(pod, dstPod))
+	assert.Panics(t, func() { MustInjectTypeMeta(srcPod, pod) })
+}
+
+func TestInjectTypeMetaWithUnstructured(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Test"}
+	src := &unstructured.Unstructured{}
+	src.SetGroupVersionKind(gvk)
+
+	// When dst have no GVK
+	dst := &unstructured.Unstructured{}
+	assert.NoError(t, InjectTypeMeta(src, dst))
+	assert.Equal(t, gvk, dst.GroupVersionKind())
+
+	// When dst already have GVK
+	dst = &unstructured.Unstructured{}
+	dst.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: "v2", Kind: "Test"})
+	assert.NoError(t, InjectTypeMeta(src, dst))
+	assert.Equal(t, "v2", dst.GroupVersionKind().Version)
+}
@@ -0,0 +1,399 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// WebhookValidator permit to validate object on admission webhook
+// It return the warnings and the errors of all fields, instead to stop on the first error
+type WebhookValidator[T client.Object] interface {
+
+	// ValidateCreate permit to validate the object on creation
+	ValidateCreate(ctx context.Context, c client.Client, o T) (warnings admission.Warnings, errs field.ErrorList)
+
+	// ValidateUpdate permit to validate the object on update
+	ValidateUpdate(ctx context.Context, c client.Client, oldObject T, o T) (warnings admission.Warnings, errs field.ErrorList)
+
+	// ValidateDelete permit to validate the object on deletion
+	ValidateDelete(ctx context.Context, c client.Client, o T) (warnings admission.Warnings, errs field.ErrorList)
+}
+
+// WebhookDefaulter permit to set the default values of object on admission webhook
+type WebhookDefaulter[T client.Object] func(ctx context.Context, c client.Client, o T) (errs field.ErrorList)
+
+// WebhookValidatorFuncs is WebhookValidator from functions. The nil functions not validate anything
+type WebhookValidatorFuncs[T client.Object] struct {
+	CreateFunc func(ctx context.Context, c client.Client, o T) (warnings admission.Warnings, errs field.ErrorList)
+	UpdateFunc func(ctx context.Context, c client.Client, oldObject T, o T) (warnings admission.Warnings, errs field.ErrorList)
+	DeleteFunc func(ctx context.Context, c client.Client, o T) (warnings admission.Warnings, errs field.ErrorList)
+}
+
+func (h WebhookValidatorFuncs[T]) ValidateCreate(ctx context.Context, c client.Client, o T) (warnings admission.Warnings, errs field.ErrorList) {
+	if h.CreateFunc == nil {
+		return nil, nil
+	}
+	return h.CreateFunc(ctx, c, o)
+}
+
+func (h WebhookValidatorFuncs[T]) ValidateUpdate(ctx context.Context, c client.Client, oldObject T, o T) (warnings admission.Warnings, errs field.ErrorList) {
+	if h.UpdateFunc == nil {
+		return nil, nil
+	}
+	return h.UpdateFunc(ctx, c, oldObject, o)
+}
+
+func (h WebhookValidatorFuncs[T]) ValidateDelete(ctx context.Context, c client.Client, o T) (warnings admission.Warnings, errs field.ErrorList) {
+	if h.DeleteFunc == nil {
+		return nil, nil
+	}
+	return h.DeleteFunc(ctx, c, o)
+}
+
+// NewRuleValidator permit to validate the object with rule on create and update, like cross-field rules
+func NewRuleValidator[T client.Object](rule func(ctx context.Context, c client.Client, o T) (warnings admission.Warnings, errs field.ErrorList)) WebhookValidator[T] {
+	return WebhookValidatorFuncs[T]{
+		CreateFunc: rule,
+		UpdateFunc: func(ctx context.Context, c client.Client, oldObject T, o T) (warnings admission.Warnings, errs field.ErrorList) {
+			return rule(ctx, c, o)
+		},
+	}
+}
+
+// NewImmutableFieldsValidator permit to forbid to change the fields on update
+// The paths are like 'spec.name'. The items of array are noted 'spec.users[]', and the values of map 'spec.labels{}'
+// Like the CEL rule 'self == oldSelf' added by the tag '@immutable', a field can be set when it not exist on old object and it can be removed
+func NewImmutableFieldsValidator[T client.Object](paths ...string) WebhookValidator[T] {
+	return WebhookValidatorFuncs[T]{
+		UpdateFunc: func(ctx context.Context, c client.Client, oldObject T, o T) (warnings admission.Warnings, errs field.ErrorList) {
+			oldContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(oldObject)
+			if err != nil {
+				return nil, field.ErrorList{field.InternalError(nil, errors.Wrap(err, "Error when convert old object to unstructured"))}
+			}
+			content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+			if err != nil {
+				return nil, field.ErrorList{field.InternalError(nil, errors.Wrap(err, "Error when convert object to unstructured"))}
+			}
+
+			for _, path := range paths {
+				values := getFieldValues(content, nil, splitFieldPath(path), map[string]fieldValue{})
+				for key, oldValue := range getFieldValues(oldContent, nil, splitFieldPath(path), map[string]fieldValue{}) {
+					value, ok := values[key]
+					if !ok {
+						continue
+					}
+					if !reflect.DeepEqual(oldValue.value, value.value) {
+						errs = append(errs, field.Invalid(value.path, value.value, helper.ImmutableMessage))
+					}
+				}
+			}
+
+			return nil, errs
+		},
+	}
+}
+
+// NewImmutableFieldsValidatorFromCRD permit to forbid to change the fields marked as immutable on the version of CRD
+// The fields are marked as immutable by the CRD post-processing tag '@immutable'
+func NewImmutableFieldsValidatorFromCRD[T client.Object](crd *apiextensionsv1.CustomResourceDefinition, version string) (WebhookValidator[T], error) {
+	paths, err := helper.ImmutableFieldsFromCRD(crd, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewImmutableFieldsValidator[T](paths...), nil
+}
+
+// NewReferenceExistValidator permit to check on create and update that the object referenced by field exist
+// getReference return nil when the reference is not set. The namespace of object is used when the namespace of reference is empty
+func NewReferenceExistValidator[T client.Object](fieldPath *field.Path, reference client.Object, getReference func(o T) *types.NamespacedName) WebhookValidator[T] {
+	return NewRuleValidator(func(ctx context.Context, c client.Client, o T) (warnings admission.Warnings, errs field.ErrorList) {
+		key := getReference(o)
+		if key == nil {
+			return nil, nil
+		}
+		if key.Namespace == "" {
+			key.Namespace = o.GetNamespace()
+		}
+
+		if err := c.Get(ctx, *key, reference.DeepCopyObject().(client.Object)); err != nil {
+			if k8serrors.IsNotFound(err) {
+				return nil, field.ErrorList{field.NotFound(fieldPath, key.String())}
+			}
+			return nil, field.ErrorList{field.InternalError(fieldPath, errors.Wrapf(err, "Error when get reference %s", key.String()))}
+		}
+
+		return nil, nil
+	})
+}
+
+// WebhookBuilder permit to build typed validation and defaulting webhook from validators and defaulters
+type WebhookBuilder[T client.Object] struct {
+	object     T
+	client     client.Client
+	validators []WebhookValidator[T]
+	defaulters []WebhookDefaulter[T]
+}
+
+// NewWebhookBuilder permit to start to build webhook for the object type
+func NewWebhookBuilder[T client.Object](o T) *WebhookBuilder[T] {
+	return &WebhookBuilder[T]{
+		object:     o,
+		validators: make([]WebhookValidator[T], 0),
+		defaulters: make([]WebhookDefaulter[T], 0),
+	}
+}
+
+// WithClient permit to set the client used by validators and defaulters
+// It is optional, the client given to Register is used when not set
+func (h *WebhookBuilder[T]) WithClient(client client.Client) *WebhookBuilder[T] {
+	h.client = client
+	return h
+}
+
+// WithValidators permit to add validators. They are all called in order, and their errors are aggregated
+func (h *WebhookBuilder[T]) WithValidators(validators ...WebhookValidator[T]) *WebhookBuilder[T] {
+	h.validators = append(h.validators, validators...)
+	return h
+}
+
+// WithDefaulters permit to add defaulters. They are called in order
+func (h *WebhookBuilder[T]) WithDefaulters(defaulters ...WebhookDefaulter[T]) *WebhookBuilder[T] {
+	h.defaulters = append(h.defaulters, defaulters...)
+	return h
+}
+
+// Build permit to get the webhook
+func (h *WebhookBuilder[T]) Build() (*BasicWebhook[T], error) {
+	if isNilObject(h.object) {
+		return nil, errors.New("Object can't be nil")
+	}
+	if len(h.validators) == 0 && len(h.defaulters) == 0 {
+		return nil, errors.New("Webhook need at least one validator or defaulter")
+	}
+
+	w := &BasicWebhook[T]{
+		object:     h.object,
+		client:     h.client,
+		validators: h.validators,
+		defaulters: h.defaulters,
+	}
+	if h.client != nil {
+		w.scheme = h.client.Scheme()
+	}
+
+	return w, nil
+}
+
+// BasicWebhook is the webhook built by WebhookBuilder
+// It implement admission.CustomValidator and admission.CustomDefaulter
+type BasicWebhook[T client.Object] struct {
+	object     T
+	client     client.Client
+	scheme     *runtime.Scheme
+	validators []WebhookValidator[T]
+	defaulters []WebhookDefaulter[T]
+}
+
+// Register permit to register the webhook on manager. It is a WebhookRegister
+func (h *BasicWebhook[T]) Register(mgr ctrl.Manager, client client.Client) error {
+	if h.client == nil {
+		h.client = client
+	}
+	h.scheme = mgr.GetScheme()
+
+	b := ctrl.NewWebhookManagedBy(mgr).For(h.object)
+	if len(h.validators) > 0 {
+		b = b.WithValidator(h)
+	}
+	if len(h.defaulters) > 0 {
+		b = b.WithDefaulter(h)
+	}
+
+	if err := b.Complete(); err != nil {
+		return errors.Wrapf(err, "Error when register webhook of %T", h.object)
+	}
+
+	return nil
+}
+
+func (h *BasicWebhook[T]) Default(ctx context.Context, obj runtime.Object) error {
+	o, err := h.cast(obj)
+	if err != nil {
+		return err
+	}
+
+	errs := field.ErrorList{}
+	for _, defaulter := range h.defaulters {
+		errs = append(errs, defaulter(ctx, h.client, o)...)
+	}
+
+	return h.toError(o, errs)
+}
+
+func (h *BasicWebhook[T]) ValidateCreate(ctx context.Context, obj runtime.Object) (warnings admission.Warnings, err error) {
+	o, err := h.cast(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := field.ErrorList{}
+	for _, validator := range h.validators {
+		validatorWarnings, validatorErrs := validator.ValidateCreate(ctx, h.client, o)
+		warnings = append(warnings, validatorWarnings...)
+		errs = append(errs, validatorErrs...)
+	}
+
+	return warnings, h.toError(o, errs)
+}
+
+func (h *BasicWebhook[T]) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (warnings admission.Warnings, err error) {
+	oldObject, err := h.cast(oldObj)
+	if err != nil {
+		return nil, err
+	}
+	o, err := h.cast(newObj)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := field.ErrorList{}
+	for _, validator := range h.validators {
+		validatorWarnings, validatorErrs := validator.ValidateUpdate(ctx, h.client, oldObject, o)
+		warnings = append(warnings, validatorWarnings...)
+		errs = append(errs, validatorErrs...)
+	}
+
+	return warnings, h.toError(o, errs)
+}
+
+func (h *BasicWebhook[T]) ValidateDelete(ctx context.Context, obj runtime.Object) (warnings admission.Warnings, err error) {
+	o, err := h.cast(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := field.ErrorList{}
+	for _, validator := range h.validators {
+		validatorWarnings, validatorErrs := validator.ValidateDelete(ctx, h.client, o)
+		warnings = append(warnings, validatorWarnings...)
+		errs = append(errs, validatorErrs...)
+	}
+
+	return warnings, h.toError(o, errs)
+}
+
+// cast permit to get the typed object
+func (h *BasicWebhook[T]) cast(obj runtime.Object) (o T, err error) {
+	o, ok := obj.(T)
+	if !ok {
+		return o, errors.Errorf("Expected object of type %T, but got %T", h.object, obj)
+	}
+
+	return o, nil
+}
+
+// toError permit to aggregate the field errors on invalid error
+// The kind is read from the object when the scheme is not yet known, before Register
+func (h *BasicWebhook[T]) toError(o T, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	gvk := o.GetObjectKind().GroupVersionKind()
+	if h.scheme != nil {
+		var err error
+		if gvk, err = apiutil.GVKForObject(o, h.scheme); err != nil {
+			return errors.Wrap(errs.ToAggregate(), err.Error())
+		}
+	}
+
+	return k8serrors.NewInvalid(gvk.GroupKind(), o.GetName(), errs)
+}
+
+// fieldValue is the value of field and its path
+type fieldValue struct {
+	path  *field.Path
+	value any
+}
+
+// splitFieldPath permit to split path like 'spec.users[].name' to 'spec', 'users', '[]', 'name'
+func splitFieldPath(path string) (segments []string) {
+	for _, part := range strings.Split(path, ".") {
+		suffixes := make([]string, 0)
+		for {
+			if strings.HasSuffix(part, "[]") {
+				suffixes = append([]string{"[]"}, suffixes...)
+				part = strings.TrimSuffix(part, "[]")
+			} else if strings.HasSuffix(part, "{}") {
+				suffixes = append([]string{"{}"}, suffixes...)
+				part = strings.TrimSuffix(part, "{}")
+			} else {
+				break
+			}
+		}
+		if part != "" {
+			segments = append(segments, part)
+		}
+		segments = append(segments, suffixes...)
+	}
+
+	return segments
+}
+
+// getFieldValues permit to get the values of field from unstructured content, indexed by their path
+// The items of array and the values of map are expanded
+func getFieldValues(content any, path *field.Path, segments []string, values map[string]fieldValue) map[string]fieldValue {
+	if len(segments) == 0 {
+		values[path.String()] = fieldValue{path: path, value: content}
+		return values
+	}
+
+	switch segments[0] {
+	case "[]":
+		items, ok := content.([]any)
+		if !ok {
+			return values
+		}
+		for i, item := range items {
+			getFieldValues(item, path.Index(i), segments[1:], values)
+		}
+	case "{}":
+		m, ok := content.(map[string]any)
+		if !ok {
+			return values
+		}
+		for key, item := range m {
+			getFieldValues(item, path.Key(key), segments[1:], values)
+		}
+	default:
+		m, ok := content.(map[string]any)
+		if !ok {
+			return values
+		}
+		item, ok := m[segments[0]]
+		if !ok {
+			return values
+		}
+		childPath := field.NewPath(segments[0])
+		if path != nil {
+			childPath = path.Child(segments[0])
+		}
+		getFieldValues(item, childPath, segments[1:], values)
+	}
+
+	return values
+}
@@ -0,0 +1,212 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestWebhookBuilder(t *testing.T) {
+	// When no validator and defaulter
+	_, err := NewWebhookBuilder(&corev1.Pod{}).Build()
+	assert.Error(t, err)
+
+	// When object is nil
+	var pod *corev1.Pod
+	_, err = NewWebhookBuilder(pod).WithValidators(NewImmutableFieldsValidator[*corev1.Pod]("spec.nodeName")).Build()
+	assert.Error(t, err)
+
+	// When all is right
+	w, err := NewWebhookBuilder(&corev1.Pod{}).
+		WithClient(fake.NewClientBuilder().Build()).
+		WithValidators(NewImmutableFieldsValidator[*corev1.Pod]("spec.nodeName")).
+		WithDefaulters(func(ctx context.Context, c client.Client, o *corev1.Pod) field.ErrorList {
+			return nil
+		}).
+		Build()
+	assert.NoError(t, err)
+	assert.NotNil(t, w)
+	assert.Implements(t, (*admission.CustomValidator)(nil), w)
+	assert.Implements(t, (*admission.CustomDefaulter)(nil), w)
+}
+
+func TestBasicWebhookValidate(t *testing.T) {
+	ruleValidator := NewRuleValidator(func(ctx context.Context, c client.Client, o *corev1.Pod) (warnings admission.Warnings, errs field.ErrorList) {
+		if o.Spec.NodeName == "" {
+			return admission.Warnings{"nodeName is empty"}, nil
+		}
+		if o.Spec.NodeName == o.Spec.Hostname {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "hostname"), o.Spec.Hostname, "must be different from nodeName"))
+		}
+		return nil, errs
+	})
+	deleteValidator := WebhookValidatorFuncs[*corev1.Pod]{
+		DeleteFunc: func(ctx context.Context, c client.Client, o *corev1.Pod) (warnings admission.Warnings, errs field.ErrorList) {
+			return nil, field.ErrorList{field.Forbidden(field.NewPath("metadata", "name"), "can't be deleted")}
+		},
+	}
+
+	w, err := NewWebhookBuilder(&corev1.Pod{}).
+		WithClient(fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()).
+		WithValidators(ruleValidator, NewImmutableFieldsValidator[*corev1.Pod]("spec.nodeName"), deleteValidator).
+		Build()
+	assert.NoError(t, err)
+
+	// When create return warnings
+	warnings, err := w.ValidateCreate(context.Background(), &corev1.Pod{})
+	assert.NoError(t, err)
+	assert.Equal(t, admission.Warnings{"nodeName is empty"}, warnings)
+
+	// When create is invalid
+	_, err = w.ValidateCreate(context.Background(), &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test"}, Spec: corev1.PodSpec{NodeName: "node", Hostname: "node"}})
+	assert.Error(t, err)
+	assert.True(t, k8serrors.IsInvalid(err))
+
+	// When update aggregate errors of all validators
+	_, err = w.ValidateUpdate(
+		context.Background(),
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test"}, Spec: corev1.PodSpec{NodeName: "node1"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test"}, Spec: corev1.PodSpec{NodeName: "node2", Hostname: "node2"}},
+	)
+	assert.Error(t, err)
+	assert.True(t, k8serrors.IsInvalid(err))
+	assert.Len(t, err.(*k8serrors.StatusError).ErrStatus.Details.Causes, 2)
+
+	// When delete
+	_, err = w.ValidateDelete(context.Background(), &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test"}})
+	assert.Error(t, err)
+
+	// When bad object type
+	_, err = w.ValidateCreate(context.Background(), &corev1.ConfigMap{})
+	assert.Error(t, err)
+
+	// When scheme is not known
+	w, err = NewWebhookBuilder(&corev1.Pod{}).
+		WithValidators(deleteValidator).
+		Build()
+	assert.NoError(t, err)
+	_, err = w.ValidateDelete(context.Background(), &corev1.Pod{TypeMeta: metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"}, ObjectMeta: metav1.ObjectMeta{Name: "test"}})
+	assert.Error(t, err)
+	assert.True(t, k8serrors.IsInvalid(err))
+	assert.Equal(t, "Pod", err.(*k8serrors.StatusError).ErrStatus.Details.Kind)
+}
+
+func TestBasicWebhookDefault(t *testing.T) {
+	w, err := NewWebhookBuilder(&corev1.Pod{}).
+		WithClient(fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()).
+		WithDefaulters(
+			func(ctx context.Context, c client.Client, o *corev1.Pod) field.ErrorList {
+				if o.Spec.RestartPolicy == "" {
+					o.Spec.RestartPolicy = corev1.RestartPolicyAlways
+				}
+				return nil
+			},
+			func(ctx context.Context, c client.Client, o *corev1.Pod) field.ErrorList {
+				if o.Spec.NodeName == "bad" {
+					return field.ErrorList{field.Invalid(field.NewPath("spec", "nodeName"), o.Spec.NodeName, "bad node")}
+				}
+				return nil
+			},
+		).
+		Build()
+	assert.NoError(t, err)
+
+	// When defaulters set default values
+	pod := &corev1.Pod{}
+	assert.NoError(t, w.Default(context.Background(), pod))
+	assert.Equal(t, corev1.RestartPolicyAlways, pod.Spec.RestartPolicy)
+
+	// When defaulter return errors
+	assert.Error(t, w.Default(context.Background(), &corev1.Pod{Spec: corev1.PodSpec{NodeName: "bad"}}))
+}
+
+func TestImmutableFieldsValidator(t *testing.T) {
+	validator := NewImmutableFieldsValidator[*corev1.Pod]("spec.nodeName", "spec.containers[].image", "metadata.labels{}")
+	oldPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"app": "test"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "test", Image: "image:1"}},
+		},
+	}
+
+	// When fields not change
+	_, errs := validator.ValidateUpdate(context.Background(), nil, oldPod, oldPod.DeepCopy())
+	assert.Empty(t, errs)
+
+	// When field is set
+	pod := oldPod.DeepCopy()
+	pod.Spec.NodeName = "node"
+	_, errs = validator.ValidateUpdate(context.Background(), nil, oldPod, pod)
+	assert.Empty(t, errs)
+
+	// When field change
+	oldPod = pod.DeepCopy()
+	pod.Spec.NodeName = "node2"
+	pod.Spec.Containers[0].Image = "image:2"
+	pod.Labels["app"] = "test2"
+	pod.Labels["foo"] = "bar"
+	_, errs = validator.ValidateUpdate(context.Background(), nil, oldPod, pod)
+	assert.Len(t, errs, 3)
+	paths := make([]string, 0, len(errs))
+	for _, err := range errs {
+		paths = append(paths, err.Field)
+	}
+	assert.ElementsMatch(t, []string{"spec.nodeName", "spec.containers[0].image", "metadata.labels[app]"}, paths)
+
+	// When field is removed
+	pod = oldPod.DeepCopy()
+	pod.Spec.NodeName = ""
+	_, errs = validator.ValidateUpdate(context.Background(), nil, oldPod, pod)
+	assert.Empty(t, errs)
+
+	// When create and delete
+	_, errs = validator.ValidateCreate(context.Background(), nil, pod)
+	assert.Empty(t, errs)
+	_, errs = validator.ValidateDelete(context.Background(), nil, pod)
+	assert.Empty(t, errs)
+}
+
+func TestReferenceExistValidator(t *testing.T) {
+	fakeClient := fake.NewClientBuilder().WithObjects(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: "default"}}).Build()
+	validator := NewReferenceExistValidator(field.NewPath("spec", "secretName"), &corev1.Secret{}, func(o *corev1.Pod) *types.NamespacedName {
+		if o.Spec.ServiceAccountName == "" {
+			return nil
+		}
+		return &types.NamespacedName{Name: o.Spec.ServiceAccountName}
+	})
+
+	// When reference not set
+	_, errs := validator.ValidateCreate(context.Background(), fakeClient, &corev1.Pod{})
+	assert.Empty(t, errs)
+
+	// When reference exist
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default"}, Spec: corev1.PodSpec{ServiceAccountName: "secret"}}
+	_, errs = validator.ValidateCreate(context.Background(), fakeClient, pod)
+	assert.Empty(t, errs)
+
+	// When reference not exist
+	pod.Spec.ServiceAccountName = "foo"
+	_, errs = validator.ValidateUpdate(context.Background(), fakeClient, pod, pod)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, field.ErrorTypeNotFound, errs[0].Type)
+	assert.Equal(t, "spec.secretName", errs[0].Field)
+}
+
+func TestSplitFieldPath(t *testing.T) {
+	assert.Equal(t, []string{"spec", "name"}, splitFieldPath("spec.name"))
+	assert.Equal(t, []string{"spec", "users", "[]", "name"}, splitFieldPath("spec.users[].name"))
+	assert.Equal(t, []string{"spec", "matrix", "[]", "[]"}, splitFieldPath("spec.matrix[][]"))
+	assert.Equal(t, []string{"spec", "labels", "{}"}, splitFieldPath("spec.labels{}"))
+}
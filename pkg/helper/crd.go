@@ -1,11 +1,23 @@
 package helper
 
 import (
+	"sort"
+
+	"emperror.dev/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
 )
 
+const (
+	// ImmutableRule is the CEL rule added by the CRD post-processing tag '@immutable' to forbid to change the field
+	ImmutableRule = "self == oldSelf"
+
+	// ImmutableMessage is the message when ImmutableRule not match
+	ImmutableMessage = "Value is immutable"
+)
+
 // HasCRD checks if the Kubernetes server supports the given groupVersion for CustomResourceDefinition.
 //
 // Parameters:
@@ -22,3 +34,107 @@ func HasCRD(kclient *kubernetes.Clientset, groupVersion schema.GroupVersion) boo
 
 	return true
 }
+
+// ImmutableFieldsFromCRD permit to get the path of fields that have the CEL rule ImmutableRule on the version of CRD
+// The path are like 'spec.name'. The items of array are noted 'spec.users[]', and the values of map 'spec.labels{}'
+func ImmutableFieldsFromCRD(crd *apiextensionsv1.CustomResourceDefinition, version string) (paths []string, err error) {
+	for _, crdVersion := range crd.Spec.Versions {
+		if crdVersion.Name != version {
+			continue
+		}
+		paths = make([]string, 0)
+		if crdVersion.Schema == nil {
+			return paths, nil
+		}
+
+		// The sub schemas have the same path than their parent, so the path can be found several times
+		isFound := map[string]bool{}
+		WalkSchema(crdVersion.Schema.OpenAPIV3Schema.DeepCopy(), "", func(path string, props *apiextensionsv1.JSONSchemaProps) bool {
+			if path == "" || isFound[path] {
+				return true
+			}
+			for _, validation := range props.XValidations {
+				if validation.Rule == ImmutableRule {
+					paths = append(paths, path)
+					isFound[path] = true
+					break
+				}
+			}
+
+			return true
+		})
+
+		return paths, nil
+	}
+
+	return nil, errors.Errorf("Version %s not found on CRD %s", version, crd.Name)
+}
+
+// SchemaVisitor is called on each schema node with its path, like 'spec.users[]'
+// It can change the node. When it return false, the children of node are not walked
+type SchemaVisitor func(path string, props *apiextensionsv1.JSONSchemaProps) (walkChildren bool)
+
+// WalkSchema permit to walk on all nodes of schema, parent before children
+// It walk on properties, items, additional properties and sub schemas. The properties are walked in sorted order.
+// The items of array are noted 'spec.users[]', the values of map 'spec.labels{}', and the sub schemas have the path of their parent
+func WalkSchema(props *apiextensionsv1.JSONSchemaProps, path string, visitor SchemaVisitor) {
+	if props == nil {
+		return
+	}
+
+	if !visitor(path, props) {
+		return
+	}
+
+	keys := make([]string, 0, len(props.Properties))
+	for key := range props.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		// The map value is not addressable, so it need to set it back
+		item := props.Properties[key]
+		WalkSchema(&item, JoinSchemaPath(path, key), visitor)
+		props.Properties[key] = item
+	}
+
+	if props.Items != nil {
+		WalkSchema(props.Items.Schema, path+"[]", visitor)
+		for i := range props.Items.JSONSchemas {
+			WalkSchema(&props.Items.JSONSchemas[i], path+"[]", visitor)
+		}
+	}
+
+	if props.AdditionalProperties != nil {
+		WalkSchema(props.AdditionalProperties.Schema, path+"{}", visitor)
+	}
+
+	for _, subSchemas := range [][]apiextensionsv1.JSONSchemaProps{props.AllOf, props.AnyOf, props.OneOf} {
+		for i := range subSchemas {
+			WalkSchema(&subSchemas[i], path, visitor)
+		}
+	}
+	WalkSchema(props.Not, path, visitor)
+}
+
+// WalkCRD permit to walk on schema of all versions of CRD
+// The root of each schema have empty path
+func WalkCRD(crd *apiextensionsv1.CustomResourceDefinition, visitor func(version string, path string, props *apiextensionsv1.JSONSchemaProps) (walkChildren bool)) {
+	for i, version := range crd.Spec.Versions {
+		if version.Schema == nil {
+			continue
+		}
+		WalkSchema(crd.Spec.Versions[i].Schema.OpenAPIV3Schema, "", func(path string, props *apiextensionsv1.JSONSchemaProps) bool {
+			return visitor(version.Name, path, props)
+		})
+	}
+}
+
+// JoinSchemaPath permit to get the path of property key on schema path
+func JoinSchemaPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
@@ -0,0 +1,118 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestImmutableFieldsFromCRD(t *testing.T) {
+	immutable := []apiextensionsv1.ValidationRule{{Rule: ImmutableRule, Message: ImmutableMessage}}
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{
+					Name: "v1",
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]apiextensionsv1.JSONSchemaProps{
+								"spec": {
+									Type: "object",
+									Properties: map[string]apiextensionsv1.JSONSchemaProps{
+										"name":     {Type: "string", XValidations: immutable},
+										"replicas": {Type: "integer", XValidations: []apiextensionsv1.ValidationRule{{Rule: "self >= 0"}}},
+										"users": {
+											Type: "array",
+											Items: &apiextensionsv1.JSONSchemaPropsOrArray{
+												Schema: &apiextensionsv1.JSONSchemaProps{
+													Type: "object",
+													Properties: map[string]apiextensionsv1.JSONSchemaProps{
+														"id": {Type: "string", XValidations: immutable},
+													},
+												},
+											},
+										},
+										"labels": {
+											Type: "object",
+											AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+												Schema: &apiextensionsv1.JSONSchemaProps{Type: "string", XValidations: immutable},
+											},
+										},
+										"backend": {
+											Type: "object",
+											AllOf: []apiextensionsv1.JSONSchemaProps{
+												{Properties: map[string]apiextensionsv1.JSONSchemaProps{"type": {XValidations: immutable}}},
+											},
+											AnyOf: []apiextensionsv1.JSONSchemaProps{
+												{Properties: map[string]apiextensionsv1.JSONSchemaProps{"type": {XValidations: immutable}}},
+											},
+											OneOf: []apiextensionsv1.JSONSchemaProps{
+												{Properties: map[string]apiextensionsv1.JSONSchemaProps{"s3": {XValidations: immutable}}},
+												{Properties: map[string]apiextensionsv1.JSONSchemaProps{"gcs": {XValidations: immutable}}},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					Name: "v2",
+				},
+			},
+		},
+	}
+
+	// When version exist
+	paths, err := ImmutableFieldsFromCRD(crd, "v1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"spec.backend.type", "spec.backend.s3", "spec.backend.gcs", "spec.labels{}", "spec.name", "spec.users[].id"}, paths)
+	assert.Len(t, crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].Properties, 5)
+
+	// When version has no schema
+	paths, err = ImmutableFieldsFromCRD(crd, "v2")
+	assert.NoError(t, err)
+	assert.Empty(t, paths)
+
+	// When version not exist
+	_, err = ImmutableFieldsFromCRD(crd, "v3")
+	assert.Error(t, err)
+}
+
+func TestWalkSchema(t *testing.T) {
+	schema := &apiextensionsv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"spec": {
+				Type: "object",
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"users": {
+						Type:  "array",
+						Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}},
+					},
+					"labels": {
+						Type:                 "object",
+						AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}},
+					},
+				},
+				OneOf: []apiextensionsv1.JSONSchemaProps{{Required: []string{"users"}}},
+				Not:   &apiextensionsv1.JSONSchemaProps{Required: []string{"labels"}},
+			},
+			"status": {Type: "object", Properties: map[string]apiextensionsv1.JSONSchemaProps{"phase": {Type: "string"}}},
+		},
+	}
+
+	// It walk on all nodes, parent before children, and it can change them
+	paths := make([]string, 0)
+	WalkSchema(schema, "", func(path string, props *apiextensionsv1.JSONSchemaProps) bool {
+		paths = append(paths, path)
+		props.Description = "walked"
+		return path != "status"
+	})
+	assert.Equal(t, []string{"", "spec", "spec.labels", "spec.labels{}", "spec.users", "spec.users[]", "spec", "spec", "status"}, paths)
+	assert.Equal(t, "walked", schema.Properties["spec"].Properties["users"].Items.Schema.Description)
+	assert.Empty(t, schema.Properties["status"].Properties["phase"].Description)
+}
@@ -0,0 +1,208 @@
+package mock
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ActionHook is the hook of reconciler action called by the reconcilers
+type ActionHook string
+
+const (
+	HookConfigure ActionHook = "Configure"
+	HookRead      ActionHook = "Read"
+	HookCreate    ActionHook = "Create"
+	HookUpdate    ActionHook = "Update"
+	HookDelete    ActionHook = "Delete"
+	HookFinalize  ActionHook = "Finalize"
+	HookDiff      ActionHook = "Diff"
+	HookOnError   ActionHook = "OnError"
+	HookOnSuccess ActionHook = "OnSuccess"
+)
+
+// ActionCall is the invocation of hook recorded by the mocks, with its arguments
+type ActionCall struct {
+	Hook ActionHook
+
+	// Object is a copy of the object reconciled, when the hook is called
+	Object client.Object
+
+	// Objects is a copy of the objects to create, update or delete, when the hook is called
+	Objects []client.Object
+
+	// Read is the read given to Diff or Finalize
+	Read any
+
+	// Diff is the diff given to OnSuccess, or the diff returned by Diff
+	Diff any
+
+	// Error is the error given to OnError
+	Error error
+}
+
+// actionInjection is the result and error injected on hook
+// It is returned count times, or until reset if count is 0
+type actionInjection struct {
+	result ctrl.Result
+	err    error
+	count  int
+}
+
+// ActionRecorder permit to record the calls of hooks and to inject results and errors on them
+// It is embedded by the mocks of reconciler actions
+type ActionRecorder struct {
+	mutex      sync.Mutex
+	calls      []*ActionCall
+	injections map[ActionHook]*actionInjection
+}
+
+// NewActionRecorder is the default constructor of ActionRecorder
+func NewActionRecorder() *ActionRecorder {
+	return &ActionRecorder{
+		calls:      make([]*ActionCall, 0),
+		injections: map[ActionHook]*actionInjection{},
+	}
+}
+
+// InjectError permit to return the error when the hook is called, instead to call it
+// The error is returned count times, or until ResetInjections if count is 0
+func (h *ActionRecorder) InjectError(hook ActionHook, err error, count int) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.injections[hook] = &actionInjection{err: err, count: count}
+}
+
+// InjectResult permit to return the result when the hook is called, instead to call it
+// The result is returned count times, or until ResetInjections if count is 0
+func (h *ActionRecorder) InjectResult(hook ActionHook, res ctrl.Result, count int) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.injections[hook] = &actionInjection{result: res, count: count}
+}
+
+// ResetInjections permit to remove all injected results and errors
+func (h *ActionRecorder) ResetInjections() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.injections = map[ActionHook]*actionInjection{}
+}
+
+// Calls permit to get the recorded calls, in order
+func (h *ActionRecorder) Calls() []ActionCall {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	calls := make([]ActionCall, 0, len(h.calls))
+	for _, call := range h.calls {
+		calls = append(calls, *call)
+	}
+
+	return calls
+}
+
+// CallsOf permit to get the recorded calls of hook, in order
+func (h *ActionRecorder) CallsOf(hook ActionHook) []ActionCall {
+	calls := make([]ActionCall, 0)
+	for _, call := range h.Calls() {
+		if call.Hook == hook {
+			calls = append(calls, call)
+		}
+	}
+
+	return calls
+}
+
+// Hooks permit to get the name of called hooks, in order
+func (h *ActionRecorder) Hooks() []ActionHook {
+	calls := h.Calls()
+	hooks := make([]ActionHook, 0, len(calls))
+	for _, call := range calls {
+		hooks = append(hooks, call.Hook)
+	}
+
+	return hooks
+}
+
+// ResetCalls permit to remove the recorded calls
+func (h *ActionRecorder) ResetCalls() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.calls = make([]*ActionCall, 0)
+}
+
+// AssertCalled permit to check the number of calls of hook
+func (h *ActionRecorder) AssertCalled(t *testing.T, hook ActionHook, count int) bool {
+	t.Helper()
+
+	return assert.Len(t, h.CallsOf(hook), count, "Hook %s not called %d times, hooks called: %v", hook, count, h.Hooks())
+}
+
+// AssertNotCalled permit to check that hook is never called
+func (h *ActionRecorder) AssertNotCalled(t *testing.T, hook ActionHook) bool {
+	t.Helper()
+
+	return h.AssertCalled(t, hook, 0)
+}
+
+// AssertHooks permit to check the called hooks, in order
+func (h *ActionRecorder) AssertHooks(t *testing.T, hooks ...ActionHook) bool {
+	t.Helper()
+
+	return assert.Equal(t, hooks, h.Hooks())
+}
+
+// record permit to record the call, and to get the injection of hook if exist
+func (h *ActionRecorder) record(call *ActionCall) (injection *actionInjection) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	// Copy the objects, so later mutations by reconciler are not recorded
+	call.Object = deepCopyObject(call.Object)
+	if call.Objects != nil {
+		objects := make([]client.Object, 0, len(call.Objects))
+		for _, o := range call.Objects {
+			objects = append(objects, deepCopyObject(o))
+		}
+		call.Objects = objects
+	}
+	h.calls = append(h.calls, call)
+
+	injection, isFound := h.injections[call.Hook]
+	if !isFound {
+		return nil
+	}
+	if injection.count > 0 {
+		injection.count--
+		if injection.count == 0 {
+			delete(h.injections, call.Hook)
+		}
+	}
+
+	return injection
+}
+
+// setDiff permit to record the diff returned by hook
+func (h *ActionRecorder) setDiff(call *ActionCall, diff any) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	call.Diff = diff
+}
+
+// deepCopyObject permit to copy the object, it return nil if object is nil
+func deepCopyObject(o client.Object) client.Object {
+	if o == nil || reflect.ValueOf(o).IsNil() {
+		return nil
+	}
+
+	return o.DeepCopyObject().(client.Object)
+}
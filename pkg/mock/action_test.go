@@ -0,0 +1,249 @@
+package mock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/disaster37/operator-sdk-extra/pkg/apis"
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/object"
+	"github.com/disaster37/operator-sdk-extra/pkg/test"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type testMultiPhaseObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Status            apis.BasicMultiPhaseObjectStatus `json:"status,omitempty"`
+}
+
+func (h *testMultiPhaseObject) DeepCopyObject() runtime.Object {
+	o := &testMultiPhaseObject{TypeMeta: h.TypeMeta}
+	h.ObjectMeta.DeepCopyInto(&o.ObjectMeta)
+	h.Status.DeepCopyInto(&o.Status)
+	return o
+}
+
+func (h *testMultiPhaseObject) GetStatus() object.MultiPhaseObjectStatus { return &h.Status }
+
+func newTestMultiPhaseMocks(t *testing.T, objects ...client.Object) (*test.ReconcilerHarness, *MockMultiPhaseReconcilerAction, *MockMultiPhaseStepReconcilerAction) {
+	s := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(s))
+	s.AddKnownTypeWithName(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "TestMultiPhase"}, &testMultiPhaseObject{})
+	c := test.NewFakeClient(s, objects...)
+	recorder := record.NewFakeRecorder(100)
+
+	reconciler := controller.NewBasicMultiPhaseReconciler(c, "test", "", logrus.NewEntry(logrus.New()), recorder)
+	reconciler.SetReconcileDelay(0)
+	action := NewMockMultiPhaseReconcilerAction(controller.NewBasicMultiPhaseReconcilerAction(c, "Ready", recorder))
+	stepAction := NewMockMultiPhaseStepReconcilerAction(controller.NewBasicMultiPhaseStepReconcilerAction(c, "ConfigMap", "ConfigMapReady", recorder))
+	stepAction.ReadFunc = func(ctx context.Context, o object.MultiPhaseObject, data map[string]any, logger *logrus.Entry) (read controller.MultiPhaseRead, res ctrl.Result, err error) {
+		read = controller.NewBasicMultiPhaseRead()
+		cm := &corev1.ConfigMap{}
+		if err = c.Get(ctx, client.ObjectKeyFromObject(o), cm); err == nil {
+			read.SetCurrentObjects([]client.Object{cm})
+		}
+		read.SetExpectedObjects([]client.Object{&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: o.GetName(), Namespace: o.GetNamespace()}}})
+		return read, res, nil
+	}
+
+	h := test.NewReconcilerHarness(t, c, recorder, reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		return reconciler.Reconcile(ctx, req, &testMultiPhaseObject{}, map[string]any{}, action, stepAction)
+	}))
+
+	return h, action, stepAction
+}
+
+func TestMockMultiPhaseActions(t *testing.T) {
+	o := &testMultiPhaseObject{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	key := client.ObjectKeyFromObject(o)
+	h, action, stepAction := newTestMultiPhaseMocks(t, o)
+
+	// When step action fail on create
+	stepAction.InjectError(HookCreate, errors.New("create failed"), 1)
+	_, err := h.Reconcile(key)
+	assert.Error(t, err)
+	// The multi phase reconciler call again OnError from step action when step reconciler return error
+	stepAction.AssertHooks(t, HookConfigure, HookRead, HookDiff, HookCreate, HookOnError, HookOnError)
+	if calls := stepAction.CallsOf(HookCreate); assert.Len(t, calls, 1) {
+		assert.Len(t, calls[0].Objects, 1)
+	}
+	if calls := stepAction.CallsOf(HookOnError); assert.Len(t, calls, 2) {
+		assert.ErrorContains(t, calls[0].Error, "create failed")
+		assert.ErrorContains(t, calls[1].Error, controller.ErrWhenCallStepReconcilerFromReconciler.Error())
+	}
+	action.AssertNotCalled(t, HookOnSuccess)
+	h.AssertObjectNotFound(key, &corev1.ConfigMap{})
+	h.AssertCondition(key, &testMultiPhaseObject{}, "ConfigMapReady", metav1.ConditionFalse)
+
+	// When step action succeed
+	stepAction.ResetCalls()
+	h.AssertSteady(key, &testMultiPhaseObject{})
+	h.AssertObject(key, &corev1.ConfigMap{}, nil)
+	if calls := stepAction.CallsOf(HookOnSuccess); assert.NotEmpty(t, calls) {
+		assert.True(t, calls[0].Diff.(controller.MultiPhaseDiff).NeedCreate())
+	}
+	if calls := stepAction.CallsOf(HookDiff); assert.NotEmpty(t, calls) {
+		assert.NotNil(t, calls[0].Read)
+		assert.True(t, calls[0].Diff.(controller.MultiPhaseDiff).NeedCreate())
+	}
+	action.AssertCalled(t, HookOnSuccess, len(stepAction.CallsOf(HookOnSuccess)))
+
+	// When reconciler action ask to requeue on read
+	action.InjectResult(HookRead, ctrl.Result{RequeueAfter: time.Minute}, 0)
+	stepAction.ResetCalls()
+	_, err = h.Reconcile(key)
+	assert.NoError(t, err)
+	h.AssertResult(ctrl.Result{RequeueAfter: time.Minute})
+	assert.Empty(t, stepAction.Calls())
+	action.ResetInjections()
+
+	// When hook is overridden
+	action.OnSuccessFunc = func(ctx context.Context, o object.MultiPhaseObject, data map[string]any, logger *logrus.Entry) (res ctrl.Result, err error) {
+		return ctrl.Result{RequeueAfter: time.Second}, nil
+	}
+	_, err = h.Reconcile(key)
+	assert.NoError(t, err)
+	h.AssertResult(ctrl.Result{RequeueAfter: time.Second})
+}
+
+func TestMockSentinelAction(t *testing.T) {
+	o := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	key := client.ObjectKeyFromObject(o)
+	c := test.NewFakeClient(nil, o, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: "other"}})
+	recorder := record.NewFakeRecorder(100)
+	reconciler := controller.NewBasicSentinelReconcilerWithFinalizer(c, "test", "test.example.com/finalizer", logrus.NewEntry(logrus.New()), recorder)
+	reconciler.SetReconcileDelay(0)
+	action := NewMockSentinelReconcilerAction(controller.NewBasicSentinelAction(c, recorder))
+	action.ReadFunc = func(ctx context.Context, o client.Object, data map[string]any, logger *logrus.Entry) (read controller.SentinelRead, res ctrl.Result, err error) {
+		read = controller.NewBasicSentinelRead()
+		secrets := &corev1.SecretList{}
+		if err = c.List(ctx, secrets, client.InNamespace("other")); err != nil {
+			return nil, res, err
+		}
+		objects := make([]client.Object, 0, len(secrets.Items))
+		for _, secret := range secrets.Items {
+			objects = append(objects, &secret)
+		}
+		read.SetCurrentObjects("secret", objects)
+		read.SetExpectedObjects("secret", objects)
+		return read, res, nil
+	}
+	h := test.NewReconcilerHarness(t, c, recorder, reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		return reconciler.Reconcile(ctx, req, &corev1.ConfigMap{}, map[string]any{}, action)
+	}))
+
+	// When nothing change
+	h.AssertSteady(key, &corev1.ConfigMap{})
+	action.AssertNotCalled(t, HookCreate)
+	action.AssertNotCalled(t, HookUpdate)
+	if calls := action.CallsOf(HookOnSuccess); assert.NotEmpty(t, calls) {
+		assert.False(t, calls[len(calls)-1].Diff.(controller.SentinelDiff).IsDiff())
+	}
+
+	// When finalize fail
+	action.InjectError(HookFinalize, errors.New("finalize failed"), 1)
+	assert.NoError(t, c.Delete(context.Background(), o))
+	_, err := h.Reconcile(key)
+	assert.Error(t, err)
+	h.AssertObject(key, &corev1.ConfigMap{}, func(t *testing.T, o client.Object) {
+		assert.True(t, controllerutil.ContainsFinalizer(o, "test.example.com/finalizer"))
+	})
+	if calls := action.CallsOf(HookFinalize); assert.Len(t, calls, 1) {
+		assert.NotNil(t, calls[0].Read)
+	}
+
+	// When finalize succeed
+	h.AssertSteady(key, &corev1.ConfigMap{})
+	h.AssertObjectNotFound(key, &corev1.ConfigMap{})
+	h.AssertObjectNotFound(client.ObjectKey{Namespace: "other", Name: "child"}, &corev1.Secret{})
+	action.AssertCalled(t, HookFinalize, 2)
+}
+
+func TestMockMultiPhaseActionsWhenBlocked(t *testing.T) {
+	o := &testMultiPhaseObject{ObjectMeta: metav1.ObjectMeta{
+		Name:        "test",
+		Namespace:   "default",
+		Annotations: map[string]string{controller.PauseAnnotation: "true"},
+	}}
+	key := client.ObjectKeyFromObject(o)
+	h, action, stepAction := newTestMultiPhaseMocks(t, o)
+	nextStepAction := NewMockMultiPhaseStepReconcilerAction(controller.NewBasicMultiPhaseStepReconcilerAction(h.Client(), "Secret", "SecretReady", record.NewFakeRecorder(10)))
+	reconciler := controller.NewBasicMultiPhaseReconciler(h.Client(), "test", "", logrus.NewEntry(logrus.New()), record.NewFakeRecorder(10))
+	reconciler.SetReconcileDelay(0)
+
+	// When step is blocked, the next steps are not run
+	_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key}, &testMultiPhaseObject{}, map[string]any{}, action, stepAction, nextStepAction)
+	assert.NoError(t, err)
+	stepAction.AssertNotCalled(t, HookCreate)
+	assert.Empty(t, nextStepAction.Calls())
+	action.AssertNotCalled(t, HookOnSuccess)
+	h.AssertCondition(key, &testMultiPhaseObject{}, controller.PausedCondition, metav1.ConditionTrue)
+}
+
+type testTargetClusterStepAction struct {
+	controller.MultiPhaseStepReconcilerAction
+	targetClient client.Client
+}
+
+func (h *testTargetClusterStepAction) GetTargetClient(ctx context.Context, o client.Object, logger *logrus.Entry) (client.Client, error) {
+	return h.targetClient, nil
+}
+
+func TestMockMultiPhaseStepActionGetTargetClient(t *testing.T) {
+	c := test.NewFakeClient(nil)
+	targetClient := test.NewFakeClient(nil)
+	recorder := record.NewFakeRecorder(10)
+
+	// When wrapped action not manage another cluster
+	stepAction := NewMockMultiPhaseStepReconcilerAction(controller.NewBasicMultiPhaseStepReconcilerAction(c, "ConfigMap", "ConfigMapReady", recorder))
+	current, err := stepAction.GetTargetClient(context.Background(), &testMultiPhaseObject{}, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, current)
+
+	// When wrapped action manage another cluster
+	stepAction = NewMockMultiPhaseStepReconcilerAction(&testTargetClusterStepAction{
+		MultiPhaseStepReconcilerAction: controller.NewBasicMultiPhaseStepReconcilerAction(c, "ConfigMap", "ConfigMapReady", recorder),
+		targetClient:                   targetClient,
+	})
+	current, err = stepAction.GetTargetClient(context.Background(), &testMultiPhaseObject{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, targetClient, current)
+
+	// When overridden
+	stepAction.GetTargetClientFunc = func(ctx context.Context, o client.Object, logger *logrus.Entry) (client.Client, error) {
+		return c, nil
+	}
+	current, err = stepAction.GetTargetClient(context.Background(), &testMultiPhaseObject{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, c, current)
+}
+
+func TestActionRecorderCopyObjects(t *testing.T) {
+	recorder := NewActionRecorder()
+	o := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+	child := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "child"}}
+	recorder.record(&ActionCall{Hook: HookCreate, Object: o, Objects: []client.Object{child}})
+	recorder.record(&ActionCall{Hook: HookOnError})
+
+	// When objects are changed after the call
+	o.Labels = map[string]string{"foo": "bar"}
+	child.Labels = map[string]string{"foo": "bar"}
+	calls := recorder.Calls()
+	assert.Empty(t, calls[0].Object.GetLabels())
+	assert.Empty(t, calls[0].Objects[0].GetLabels())
+	assert.Nil(t, calls[1].Object)
+}
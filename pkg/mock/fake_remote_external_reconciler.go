@@ -0,0 +1,334 @@
+package mock
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/disaster37/generic-objectmatcher/patch"
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/object"
+	"github.com/mitchellh/copystructure"
+	"github.com/stretchr/testify/assert"
+)
+
+// FakeRemoteMethod is the method of RemoteExternalReconciler called on fake
+type FakeRemoteMethod string
+
+const (
+	FakeRemoteBuild  FakeRemoteMethod = "Build"
+	FakeRemoteGet    FakeRemoteMethod = "Get"
+	FakeRemoteCreate FakeRemoteMethod = "Create"
+	FakeRemoteUpdate FakeRemoteMethod = "Update"
+	FakeRemoteDelete FakeRemoteMethod = "Delete"
+	FakeRemoteDiff   FakeRemoteMethod = "Diff"
+)
+
+// FakeRemoteCall is the call recorded by the fake
+type FakeRemoteCall struct {
+	Method       FakeRemoteMethod
+	ExternalName string
+}
+
+// fakeRemoteError is the error injected on method
+// It is returned count times, or until reset if count is 0
+type fakeRemoteError struct {
+	err   error
+	count int
+}
+
+// FakeRemoteExternalReconciler is the in-memory implementation of RemoteExternalReconciler to test remote reconcilers without mocks
+// The remote objects are stored by external name. It permit to inject latency, errors and drift, and it record the calls
+type FakeRemoteExternalReconciler[k8sObject comparable, apiObject comparable, apiClient any] struct {
+	*controller.BasicRemoteExternalReconciler[k8sObject, apiObject, apiClient]
+	build   func(k8sO k8sObject) (apiObject, error)
+	mutex   sync.Mutex
+	objects map[string]apiObject
+	calls   []FakeRemoteCall
+	errors  map[FakeRemoteMethod]*fakeRemoteError
+	latency time.Duration
+}
+
+// NewFakeRemoteExternalReconciler is the default constructor of FakeRemoteExternalReconciler
+// The build function permit to convert the k8s object to the expected remote object. The k8s object need to implement object.RemoteObject
+func NewFakeRemoteExternalReconciler[k8sObject comparable, apiObject comparable, apiClient any](client apiClient, build func(k8sO k8sObject) (apiObject, error)) *FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient] {
+	if build == nil {
+		panic("build can't be nil")
+	}
+	var k8sO k8sObject
+	if _, ok := any(k8sO).(object.RemoteObject); !ok {
+		panic(fmt.Sprintf("k8s object of type %T must implement object.RemoteObject", k8sO))
+	}
+
+	return &FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient]{
+		BasicRemoteExternalReconciler: controller.NewBasicRemoteExternalReconciler[k8sObject, apiObject, apiClient](client),
+		build:                         build,
+		objects:                       map[string]apiObject{},
+		calls:                         make([]FakeRemoteCall, 0),
+		errors:                        map[FakeRemoteMethod]*fakeRemoteError{},
+	}
+}
+
+func (h *FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient]) Build(k8sO k8sObject) (o apiObject, err error) {
+	if err = h.call(FakeRemoteBuild, k8sO); err != nil {
+		return o, err
+	}
+
+	return h.build(k8sO)
+}
+
+func (h *FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient]) Get(k8sO k8sObject) (o apiObject, err error) {
+	if err = h.call(FakeRemoteGet, k8sO); err != nil {
+		return o, err
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	current, isFound := h.objects[externalName(k8sO)]
+	if !isFound {
+		return o, nil
+	}
+
+	return copyObject(current)
+}
+
+func (h *FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient]) Create(apiO apiObject, k8sO k8sObject) (err error) {
+	if err = h.call(FakeRemoteCreate, k8sO); err != nil {
+		return err
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	name := externalName(k8sO)
+	if _, isFound := h.objects[name]; isFound {
+		return errors.Errorf("Remote object %s already exist", name)
+	}
+
+	return h.store(name, apiO)
+}
+
+func (h *FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient]) Update(apiO apiObject, k8sO k8sObject) (err error) {
+	if err = h.call(FakeRemoteUpdate, k8sO); err != nil {
+		return err
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	name := externalName(k8sO)
+	if _, isFound := h.objects[name]; !isFound {
+		return errors.Errorf("Remote object %s not found", name)
+	}
+
+	return h.store(name, apiO)
+}
+
+func (h *FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient]) Delete(k8sO k8sObject) (err error) {
+	if err = h.call(FakeRemoteDelete, k8sO); err != nil {
+		return err
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	delete(h.objects, externalName(k8sO))
+
+	return nil
+}
+
+func (h *FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient]) Diff(currentOject apiObject, expectedObject apiObject, originalObject apiObject, k8sO k8sObject, ignoresDiff ...patch.CalculateOption) (patchResult *patch.PatchResult, err error) {
+	if err = h.call(FakeRemoteDiff, k8sO); err != nil {
+		return nil, err
+	}
+
+	return h.BasicRemoteExternalReconciler.Diff(currentOject, expectedObject, originalObject, k8sO, ignoresDiff...)
+}
+
+// SetObject permit to set the remote object, like it already exist before the reconcile (adoption)
+func (h *FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient]) SetObject(externalName string, o apiObject) (err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.store(externalName, o)
+}
+
+// GetObject permit to get a copy of the remote object
+func (h *FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient]) GetObject(externalName string) (o apiObject, isFound bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	current, isFound := h.objects[externalName]
+	if !isFound {
+		return o, false
+	}
+	o, err := copyObject(current)
+	if err != nil {
+		panic(err)
+	}
+
+	return o, true
+}
+
+// Drift permit to change the remote object outside of reconciler, like done by someone on remote API
+func (h *FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient]) Drift(externalName string, mutate func(o apiObject) apiObject) (err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	current, isFound := h.objects[externalName]
+	if !isFound {
+		return errors.Errorf("Remote object %s not found", externalName)
+	}
+	current, err = copyObject(current)
+	if err != nil {
+		return err
+	}
+
+	return h.store(externalName, mutate(current))
+}
+
+// InjectError permit to return the error when the method is called
+// The error is returned count times, or until ResetErrors if count is 0
+func (h *FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient]) InjectError(method FakeRemoteMethod, err error, count int) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.errors[method] = &fakeRemoteError{err: err, count: count}
+}
+
+// ResetErrors permit to remove all injected errors
+func (h *FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient]) ResetErrors() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.errors = map[FakeRemoteMethod]*fakeRemoteError{}
+}
+
+// SetLatency permit to wait before each call, like slow remote API
+func (h *FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient]) SetLatency(latency time.Duration) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.latency = latency
+}
+
+// Calls permit to get the recorded calls, in order
+func (h *FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient]) Calls() []FakeRemoteCall {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	calls := make([]FakeRemoteCall, len(h.calls))
+	copy(calls, h.calls)
+
+	return calls
+}
+
+// CountCalls permit to count the recorded calls of method
+func (h *FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient]) CountCalls(method FakeRemoteMethod) (count int) {
+	for _, call := range h.Calls() {
+		if call.Method == method {
+			count++
+		}
+	}
+
+	return count
+}
+
+// ResetCalls permit to remove the recorded calls
+func (h *FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient]) ResetCalls() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.calls = make([]FakeRemoteCall, 0)
+}
+
+// AssertCalled permit to check the number of calls of method
+func (h *FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient]) AssertCalled(t *testing.T, method FakeRemoteMethod, count int) bool {
+	t.Helper()
+
+	return assert.Equal(t, count, h.CountCalls(method), "Method %s called %d times, calls: %+v", method, h.CountCalls(method), h.Calls())
+}
+
+// AssertNotCalled permit to check that method is never called
+func (h *FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient]) AssertNotCalled(t *testing.T, method FakeRemoteMethod) bool {
+	t.Helper()
+
+	return h.AssertCalled(t, method, 0)
+}
+
+// AssertObject permit to check the remote object
+func (h *FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient]) AssertObject(t *testing.T, externalName string, expected apiObject) bool {
+	t.Helper()
+
+	current, isFound := h.GetObject(externalName)
+	if !assert.True(t, isFound, "Remote object %s not found", externalName) {
+		return false
+	}
+
+	return assert.Equal(t, expected, current)
+}
+
+// AssertObjectNotFound permit to check that the remote object not exist
+func (h *FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient]) AssertObjectNotFound(t *testing.T, externalName string) bool {
+	t.Helper()
+
+	_, isFound := h.GetObject(externalName)
+	return assert.False(t, isFound, "Remote object %s exist", externalName)
+}
+
+// call permit to record the call, wait the latency and return the injected error
+func (h *FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient]) call(method FakeRemoteMethod, k8sO k8sObject) (err error) {
+	h.mutex.Lock()
+	h.calls = append(h.calls, FakeRemoteCall{Method: method, ExternalName: externalName(k8sO)})
+	latency := h.latency
+	if injectedError, isFound := h.errors[method]; isFound {
+		err = injectedError.err
+		if injectedError.count > 0 {
+			injectedError.count--
+			if injectedError.count == 0 {
+				delete(h.errors, method)
+			}
+		}
+	}
+	h.mutex.Unlock()
+
+	if latency > 0 {
+		time.Sleep(latency)
+	}
+
+	return err
+}
+
+// store permit to store a copy of remote object, to not share it with the reconciler
+func (h *FakeRemoteExternalReconciler[k8sObject, apiObject, apiClient]) store(externalName string, o apiObject) (err error) {
+	o, err = copyObject(o)
+	if err != nil {
+		return err
+	}
+	h.objects[externalName] = o
+
+	return nil
+}
+
+// externalName permit to get the external name from k8s object
+// The constructor already check that k8s object implement object.RemoteObject
+func externalName[k8sObject comparable](k8sO k8sObject) string {
+	return any(k8sO).(object.RemoteObject).GetExternalName()
+}
+
+// copyObject permit to deep copy the remote object
+func copyObject[apiObject comparable](o apiObject) (apiObject, error) {
+	copied, err := copystructure.Copy(o)
+	if err != nil {
+		return o, errors.Wrap(err, "Error when copy remote object")
+	}
+	if copied == nil {
+		var empty apiObject
+		return empty, nil
+	}
+
+	return copied.(apiObject), nil
+}
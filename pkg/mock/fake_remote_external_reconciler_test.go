@@ -0,0 +1,191 @@
+package mock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/disaster37/operator-sdk-extra/pkg/apis"
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/object"
+	"github.com/disaster37/operator-sdk-extra/pkg/test"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type testRemoteObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              testRemoteSpec               `json:"spec,omitempty"`
+	Status            apis.BasicRemoteObjectStatus `json:"status,omitempty"`
+}
+
+type testRemoteSpec struct {
+	Value string `json:"value,omitempty"`
+}
+
+func (h *testRemoteObject) DeepCopyObject() runtime.Object {
+	o := &testRemoteObject{TypeMeta: h.TypeMeta, Spec: h.Spec}
+	h.ObjectMeta.DeepCopyInto(&o.ObjectMeta)
+	h.Status.DeepCopyInto(&o.Status)
+	return o
+}
+
+func (h *testRemoteObject) GetExternalName() string              { return h.Name }
+func (h *testRemoteObject) GetStatus() object.RemoteObjectStatus { return &h.Status }
+
+type testApiObject struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type testApiClient struct{}
+
+func newTestFakeRemote(t *testing.T, objects ...client.Object) (*test.ReconcilerHarness, *FakeRemoteExternalReconciler[*testRemoteObject, *testApiObject, *testApiClient]) {
+	s := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(s))
+	s.AddKnownTypeWithName(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "TestRemote"}, &testRemoteObject{})
+	c := test.NewFakeClient(s, objects...)
+	recorder := record.NewFakeRecorder(100)
+
+	fake := NewFakeRemoteExternalReconciler(&testApiClient{}, func(o *testRemoteObject) (*testApiObject, error) {
+		return &testApiObject{Name: o.GetExternalName(), Value: o.Spec.Value}, nil
+	})
+	reconciler := controller.NewBasicRemoteReconciler[*testRemoteObject, *testApiObject, *testApiClient](c, "test", "test.example.com/finalizer", logrus.NewEntry(logrus.New()), recorder)
+	reconciler.SetReconcileDelay(0)
+	action := NewMockRemoteReconcilerAction(controller.NewRemoteReconcilerAction[*testRemoteObject, *testApiObject, *testApiClient](c, recorder), func(ctx context.Context, req ctrl.Request, o object.RemoteObject, logger *logrus.Entry) (controller.RemoteExternalReconciler[*testRemoteObject, *testApiObject, *testApiClient], ctrl.Result, error) {
+		return fake, ctrl.Result{}, nil
+	})
+
+	h := test.NewReconcilerHarness(t, c, recorder, reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		return reconciler.Reconcile(ctx, req, &testRemoteObject{}, map[string]any{}, action)
+	}))
+
+	return h, fake
+}
+
+func TestFakeRemoteExternalReconciler(t *testing.T) {
+	o := &testRemoteObject{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Spec:       testRemoteSpec{Value: "foo"},
+	}
+	key := client.ObjectKeyFromObject(o)
+	h, fake := newTestFakeRemote(t, o)
+	assert.Equal(t, &testApiClient{}, fake.Client())
+
+	// When create
+	h.AssertSteady(key, &testRemoteObject{})
+	fake.AssertObject(t, "test", &testApiObject{Name: "test", Value: "foo"})
+	fake.AssertCalled(t, FakeRemoteCreate, 1)
+	fake.AssertNotCalled(t, FakeRemoteUpdate)
+
+	// When nothing change
+	fake.ResetCalls()
+	h.AssertSteady(key, &testRemoteObject{})
+	fake.AssertNotCalled(t, FakeRemoteCreate)
+	fake.AssertNotCalled(t, FakeRemoteUpdate)
+	fake.AssertCalled(t, FakeRemoteDiff, 1)
+
+	// When drift on remote
+	assert.NoError(t, fake.Drift("test", func(o *testApiObject) *testApiObject {
+		o.Value = "drift"
+		return o
+	}))
+	h.AssertSteady(key, &testRemoteObject{})
+	fake.AssertObject(t, "test", &testApiObject{Name: "test", Value: "foo"})
+	fake.AssertCalled(t, FakeRemoteUpdate, 1)
+
+	// When remote API fail one time
+	fake.InjectError(FakeRemoteUpdate, errors.New("remote API not available"), 1)
+	assert.NoError(t, h.Client().Get(context.Background(), key, o))
+	o.Spec.Value = "bar"
+	assert.NoError(t, h.Client().Update(context.Background(), o))
+	_, err := h.Reconcile(key)
+	assert.Error(t, err)
+	fake.AssertObject(t, "test", &testApiObject{Name: "test", Value: "foo"})
+	h.AssertSteady(key, &testRemoteObject{})
+	fake.AssertObject(t, "test", &testApiObject{Name: "test", Value: "bar"})
+
+	// When delete
+	assert.NoError(t, h.Client().Delete(context.Background(), o))
+	h.AssertSteady(key, &testRemoteObject{})
+	fake.AssertObjectNotFound(t, "test")
+	h.AssertObjectNotFound(key, &testRemoteObject{})
+}
+
+func TestFakeRemoteExternalReconcilerAdoption(t *testing.T) {
+	o := &testRemoteObject{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Spec:       testRemoteSpec{Value: "foo"},
+	}
+	key := client.ObjectKeyFromObject(o)
+	h, fake := newTestFakeRemote(t, o)
+
+	// When the remote object already exist
+	assert.NoError(t, fake.SetObject("test", &testApiObject{Name: "test", Value: "old"}))
+	h.AssertSteady(key, &testRemoteObject{})
+	fake.AssertNotCalled(t, FakeRemoteCreate)
+	fake.AssertCalled(t, FakeRemoteUpdate, 1)
+	fake.AssertObject(t, "test", &testApiObject{Name: "test", Value: "foo"})
+}
+
+func TestFakeRemoteExternalReconcilerFaults(t *testing.T) {
+	// When k8s object is not remote object
+	assert.Panics(t, func() {
+		NewFakeRemoteExternalReconciler(&testApiClient{}, func(o *corev1.ConfigMap) (*testApiObject, error) {
+			return &testApiObject{Name: o.GetName()}, nil
+		})
+	})
+
+	fake := NewFakeRemoteExternalReconciler(&testApiClient{}, func(o *testRemoteObject) (*testApiObject, error) {
+		return &testApiObject{Name: o.GetExternalName(), Value: o.Spec.Value}, nil
+	})
+	o := &testRemoteObject{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+
+	// When not exist
+	current, err := fake.Get(o)
+	assert.NoError(t, err)
+	assert.Nil(t, current)
+	assert.Error(t, fake.Update(&testApiObject{Name: "test"}, o))
+	assert.Error(t, fake.Drift("test", func(o *testApiObject) *testApiObject { return o }))
+
+	// When already exist
+	assert.NoError(t, fake.Create(&testApiObject{Name: "test"}, o))
+	assert.Error(t, fake.Create(&testApiObject{Name: "test"}, o))
+
+	// Stored object is not shared
+	current, err = fake.Get(o)
+	assert.NoError(t, err)
+	current.Value = "changed"
+	fake.AssertObject(t, "test", &testApiObject{Name: "test"})
+
+	// When error is injected until reset
+	fake.InjectError(FakeRemoteGet, errors.New("error"), 0)
+	_, err = fake.Get(o)
+	assert.Error(t, err)
+	_, err = fake.Get(o)
+	assert.Error(t, err)
+	fake.ResetErrors()
+	_, err = fake.Get(o)
+	assert.NoError(t, err)
+
+	// When latency
+	fake.SetLatency(10 * time.Millisecond)
+	start := time.Now()
+	assert.NoError(t, fake.Delete(o))
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+
+	// Calls are recorded
+	assert.Equal(t, FakeRemoteCall{Method: FakeRemoteDelete, ExternalName: "test"}, fake.Calls()[len(fake.Calls())-1])
+	assert.Equal(t, 5, fake.CountCalls(FakeRemoteGet))
+}
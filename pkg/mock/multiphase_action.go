@@ -0,0 +1,100 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/object"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MockMultiPhaseReconcilerAction wrap MultiPhaseReconcilerAction to record the calls of hooks and to inject results and errors on them
+// Each hook can be overridden by setting its function
+type MockMultiPhaseReconcilerAction struct {
+	*ActionRecorder
+	reconciler controller.MultiPhaseReconcilerAction
+
+	ConfigureFunc func(ctx context.Context, req ctrl.Request, o object.MultiPhaseObject, data map[string]any, logger *logrus.Entry) (res ctrl.Result, err error)
+	ReadFunc      func(ctx context.Context, o object.MultiPhaseObject, data map[string]any, logger *logrus.Entry) (res ctrl.Result, err error)
+	DeleteFunc    func(ctx context.Context, o object.MultiPhaseObject, data map[string]any, logger *logrus.Entry) (err error)
+	OnErrorFunc   func(ctx context.Context, o object.MultiPhaseObject, data map[string]any, currentErr error, logger *logrus.Entry) (res ctrl.Result, err error)
+	OnSuccessFunc func(ctx context.Context, o object.MultiPhaseObject, data map[string]any, logger *logrus.Entry) (res ctrl.Result, err error)
+}
+
+// NewMockMultiPhaseReconcilerAction is the default constructor of MockMultiPhaseReconcilerAction
+func NewMockMultiPhaseReconcilerAction(reconciler controller.MultiPhaseReconcilerAction) *MockMultiPhaseReconcilerAction {
+	if reconciler == nil {
+		panic("reconciler can't be nil")
+	}
+
+	return &MockMultiPhaseReconcilerAction{
+		ActionRecorder: NewActionRecorder(),
+		reconciler:     reconciler,
+	}
+}
+
+func (h *MockMultiPhaseReconcilerAction) Configure(ctx context.Context, req ctrl.Request, o object.MultiPhaseObject, data map[string]any, logger *logrus.Entry) (res ctrl.Result, err error) {
+	if injection := h.record(&ActionCall{Hook: HookConfigure, Object: o}); injection != nil {
+		return injection.result, injection.err
+	}
+	if h.ConfigureFunc != nil {
+		return h.ConfigureFunc(ctx, req, o, data, logger)
+	}
+
+	return h.reconciler.Configure(ctx, req, o, data, logger)
+}
+
+func (h *MockMultiPhaseReconcilerAction) Read(ctx context.Context, o object.MultiPhaseObject, data map[string]any, logger *logrus.Entry) (res ctrl.Result, err error) {
+	if injection := h.record(&ActionCall{Hook: HookRead, Object: o}); injection != nil {
+		return injection.result, injection.err
+	}
+	if h.ReadFunc != nil {
+		return h.ReadFunc(ctx, o, data, logger)
+	}
+
+	return h.reconciler.Read(ctx, o, data, logger)
+}
+
+func (h *MockMultiPhaseReconcilerAction) Delete(ctx context.Context, o object.MultiPhaseObject, data map[string]any, logger *logrus.Entry) (err error) {
+	if injection := h.record(&ActionCall{Hook: HookDelete, Object: o}); injection != nil {
+		return injection.err
+	}
+	if h.DeleteFunc != nil {
+		return h.DeleteFunc(ctx, o, data, logger)
+	}
+
+	return h.reconciler.Delete(ctx, o, data, logger)
+}
+
+func (h *MockMultiPhaseReconcilerAction) OnError(ctx context.Context, o object.MultiPhaseObject, data map[string]any, currentErr error, logger *logrus.Entry) (res ctrl.Result, err error) {
+	if injection := h.record(&ActionCall{Hook: HookOnError, Object: o, Error: currentErr}); injection != nil {
+		return injection.result, injection.err
+	}
+	if h.OnErrorFunc != nil {
+		return h.OnErrorFunc(ctx, o, data, currentErr, logger)
+	}
+
+	return h.reconciler.OnError(ctx, o, data, currentErr, logger)
+}
+
+func (h *MockMultiPhaseReconcilerAction) OnSuccess(ctx context.Context, o object.MultiPhaseObject, data map[string]any, logger *logrus.Entry) (res ctrl.Result, err error) {
+	if injection := h.record(&ActionCall{Hook: HookOnSuccess, Object: o}); injection != nil {
+		return injection.result, injection.err
+	}
+	if h.OnSuccessFunc != nil {
+		return h.OnSuccessFunc(ctx, o, data, logger)
+	}
+
+	return h.reconciler.OnSuccess(ctx, o, data, logger)
+}
+
+func (h *MockMultiPhaseReconcilerAction) Client() client.Client {
+	return h.reconciler.Client()
+}
+
+func (h *MockMultiPhaseReconcilerAction) Recorder() record.EventRecorder {
+	return h.reconciler.Recorder()
+}
@@ -0,0 +1,173 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/disaster37/k8s-objectmatcher/patch"
+	"github.com/disaster37/operator-sdk-extra/pkg/apis/shared"
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/object"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MockMultiPhaseStepReconcilerAction wrap MultiPhaseStepReconcilerAction to record the calls of hooks and to inject results and errors on them
+// Each hook can be overridden by setting its function
+// It forward GetTargetClient to the wrapped action when it implement TargetClusterReconcilerAction, so the children stay on the same cluster
+type MockMultiPhaseStepReconcilerAction struct {
+	*ActionRecorder
+	reconciler controller.MultiPhaseStepReconcilerAction
+
+	ConfigureFunc func(ctx context.Context, req ctrl.Request, o object.MultiPhaseObject, logger *logrus.Entry) (res ctrl.Result, err error)
+	ReadFunc      func(ctx context.Context, o object.MultiPhaseObject, data map[string]any, logger *logrus.Entry) (read controller.MultiPhaseRead, res ctrl.Result, err error)
+	CreateFunc    func(ctx context.Context, o object.MultiPhaseObject, data map[string]any, objects []client.Object, logger *logrus.Entry) (res ctrl.Result, err error)
+	UpdateFunc    func(ctx context.Context, o object.MultiPhaseObject, data map[string]any, objects []client.Object, logger *logrus.Entry) (res ctrl.Result, err error)
+	DeleteFunc    func(ctx context.Context, o object.MultiPhaseObject, data map[string]any, objects []client.Object, logger *logrus.Entry) (res ctrl.Result, err error)
+	OnErrorFunc   func(ctx context.Context, o object.MultiPhaseObject, data map[string]any, currentErr error, logger *logrus.Entry) (res ctrl.Result, err error)
+	OnSuccessFunc func(ctx context.Context, o object.MultiPhaseObject, data map[string]any, diff controller.MultiPhaseDiff, logger *logrus.Entry) (res ctrl.Result, err error)
+	DiffFunc      func(ctx context.Context, o object.MultiPhaseObject, read controller.MultiPhaseRead, data map[string]any, logger *logrus.Entry, ignoreDiff ...patch.CalculateOption) (diff controller.MultiPhaseDiff, res ctrl.Result, err error)
+
+	GetTargetClientFunc         func(ctx context.Context, o client.Object, logger *logrus.Entry) (targetClient client.Client, err error)
+	GetTrackedChildrenListsFunc func() []client.ObjectList
+}
+
+// NewMockMultiPhaseStepReconcilerAction is the default constructor of MockMultiPhaseStepReconcilerAction
+func NewMockMultiPhaseStepReconcilerAction(reconciler controller.MultiPhaseStepReconcilerAction) *MockMultiPhaseStepReconcilerAction {
+	if reconciler == nil {
+		panic("reconciler can't be nil")
+	}
+
+	return &MockMultiPhaseStepReconcilerAction{
+		ActionRecorder: NewActionRecorder(),
+		reconciler:     reconciler,
+	}
+}
+
+func (h *MockMultiPhaseStepReconcilerAction) Configure(ctx context.Context, req ctrl.Request, o object.MultiPhaseObject, logger *logrus.Entry) (res ctrl.Result, err error) {
+	if injection := h.record(&ActionCall{Hook: HookConfigure, Object: o}); injection != nil {
+		return injection.result, injection.err
+	}
+	if h.ConfigureFunc != nil {
+		return h.ConfigureFunc(ctx, req, o, logger)
+	}
+
+	return h.reconciler.Configure(ctx, req, o, logger)
+}
+
+func (h *MockMultiPhaseStepReconcilerAction) Read(ctx context.Context, o object.MultiPhaseObject, data map[string]any, logger *logrus.Entry) (read controller.MultiPhaseRead, res ctrl.Result, err error) {
+	if injection := h.record(&ActionCall{Hook: HookRead, Object: o}); injection != nil {
+		return controller.NewBasicMultiPhaseRead(), injection.result, injection.err
+	}
+	if h.ReadFunc != nil {
+		return h.ReadFunc(ctx, o, data, logger)
+	}
+
+	return h.reconciler.Read(ctx, o, data, logger)
+}
+
+func (h *MockMultiPhaseStepReconcilerAction) Create(ctx context.Context, o object.MultiPhaseObject, data map[string]any, objects []client.Object, logger *logrus.Entry) (res ctrl.Result, err error) {
+	if injection := h.record(&ActionCall{Hook: HookCreate, Object: o, Objects: objects}); injection != nil {
+		return injection.result, injection.err
+	}
+	if h.CreateFunc != nil {
+		return h.CreateFunc(ctx, o, data, objects, logger)
+	}
+
+	return h.reconciler.Create(ctx, o, data, objects, logger)
+}
+
+func (h *MockMultiPhaseStepReconcilerAction) Update(ctx context.Context, o object.MultiPhaseObject, data map[string]any, objects []client.Object, logger *logrus.Entry) (res ctrl.Result, err error) {
+	if injection := h.record(&ActionCall{Hook: HookUpdate, Object: o, Objects: objects}); injection != nil {
+		return injection.result, injection.err
+	}
+	if h.UpdateFunc != nil {
+		return h.UpdateFunc(ctx, o, data, objects, logger)
+	}
+
+	return h.reconciler.Update(ctx, o, data, objects, logger)
+}
+
+func (h *MockMultiPhaseStepReconcilerAction) Delete(ctx context.Context, o object.MultiPhaseObject, data map[string]any, objects []client.Object, logger *logrus.Entry) (res ctrl.Result, err error) {
+	if injection := h.record(&ActionCall{Hook: HookDelete, Object: o, Objects: objects}); injection != nil {
+		return injection.result, injection.err
+	}
+	if h.DeleteFunc != nil {
+		return h.DeleteFunc(ctx, o, data, objects, logger)
+	}
+
+	return h.reconciler.Delete(ctx, o, data, objects, logger)
+}
+
+func (h *MockMultiPhaseStepReconcilerAction) OnError(ctx context.Context, o object.MultiPhaseObject, data map[string]any, currentErr error, logger *logrus.Entry) (res ctrl.Result, err error) {
+	if injection := h.record(&ActionCall{Hook: HookOnError, Object: o, Error: currentErr}); injection != nil {
+		return injection.result, injection.err
+	}
+	if h.OnErrorFunc != nil {
+		return h.OnErrorFunc(ctx, o, data, currentErr, logger)
+	}
+
+	return h.reconciler.OnError(ctx, o, data, currentErr, logger)
+}
+
+func (h *MockMultiPhaseStepReconcilerAction) OnSuccess(ctx context.Context, o object.MultiPhaseObject, data map[string]any, diff controller.MultiPhaseDiff, logger *logrus.Entry) (res ctrl.Result, err error) {
+	if injection := h.record(&ActionCall{Hook: HookOnSuccess, Object: o, Diff: diff}); injection != nil {
+		return injection.result, injection.err
+	}
+	if h.OnSuccessFunc != nil {
+		return h.OnSuccessFunc(ctx, o, data, diff, logger)
+	}
+
+	return h.reconciler.OnSuccess(ctx, o, data, diff, logger)
+}
+
+func (h *MockMultiPhaseStepReconcilerAction) Diff(ctx context.Context, o object.MultiPhaseObject, read controller.MultiPhaseRead, data map[string]any, logger *logrus.Entry, ignoreDiff ...patch.CalculateOption) (diff controller.MultiPhaseDiff, res ctrl.Result, err error) {
+	call := &ActionCall{Hook: HookDiff, Object: o, Read: read}
+	if injection := h.record(call); injection != nil {
+		return controller.NewBasicMultiPhaseDiff(), injection.result, injection.err
+	}
+	if h.DiffFunc != nil {
+		diff, res, err = h.DiffFunc(ctx, o, read, data, logger, ignoreDiff...)
+	} else {
+		diff, res, err = h.reconciler.Diff(ctx, o, read, data, logger, ignoreDiff...)
+	}
+	h.setDiff(call, diff)
+
+	return diff, res, err
+}
+
+func (h *MockMultiPhaseStepReconcilerAction) GetTargetClient(ctx context.Context, o client.Object, logger *logrus.Entry) (targetClient client.Client, err error) {
+	if h.GetTargetClientFunc != nil {
+		return h.GetTargetClientFunc(ctx, o, logger)
+	}
+	if targetAction, ok := h.reconciler.(controller.TargetClusterReconcilerAction); ok {
+		return targetAction.GetTargetClient(ctx, o, logger)
+	}
+
+	return nil, nil
+}
+
+func (h *MockMultiPhaseStepReconcilerAction) GetTrackedChildrenLists() []client.ObjectList {
+	if h.GetTrackedChildrenListsFunc != nil {
+		return h.GetTrackedChildrenListsFunc()
+	}
+
+	return h.reconciler.GetTrackedChildrenLists()
+}
+
+func (h *MockMultiPhaseStepReconcilerAction) GetPhaseName() shared.PhaseName {
+	return h.reconciler.GetPhaseName()
+}
+
+func (h *MockMultiPhaseStepReconcilerAction) GetIgnoresDiff() []patch.CalculateOption {
+	return h.reconciler.GetIgnoresDiff()
+}
+
+func (h *MockMultiPhaseStepReconcilerAction) Client() client.Client {
+	return h.reconciler.Client()
+}
+
+func (h *MockMultiPhaseStepReconcilerAction) Recorder() record.EventRecorder {
+	return h.reconciler.Recorder()
+}
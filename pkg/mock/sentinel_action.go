@@ -0,0 +1,161 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/disaster37/k8s-objectmatcher/patch"
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MockSentinelReconcilerAction wrap SentinelReconcilerAction to record the calls of hooks and to inject results and errors on them
+// Each hook can be overridden by setting its function
+// It always implement SentinelFinalizerAction, 'Finalize' do nothing when the wrapped action not implement it
+type MockSentinelReconcilerAction struct {
+	*ActionRecorder
+	reconciler controller.SentinelReconcilerAction
+
+	ConfigureFunc func(ctx context.Context, req ctrl.Request, o client.Object, data map[string]any, logger *logrus.Entry) (res ctrl.Result, err error)
+	ReadFunc      func(ctx context.Context, o client.Object, data map[string]any, logger *logrus.Entry) (read controller.SentinelRead, res ctrl.Result, err error)
+	CreateFunc    func(ctx context.Context, o client.Object, data map[string]any, objects []client.Object, logger *logrus.Entry) (res ctrl.Result, err error)
+	UpdateFunc    func(ctx context.Context, o client.Object, data map[string]any, objects []client.Object, logger *logrus.Entry) (res ctrl.Result, err error)
+	DeleteFunc    func(ctx context.Context, o client.Object, data map[string]any, objects []client.Object, logger *logrus.Entry) (err error)
+	FinalizeFunc  func(ctx context.Context, o client.Object, data map[string]any, read controller.SentinelRead, logger *logrus.Entry) (err error)
+	OnErrorFunc   func(ctx context.Context, o client.Object, data map[string]any, currentErr error, logger *logrus.Entry) (res ctrl.Result, err error)
+	OnSuccessFunc func(ctx context.Context, o client.Object, data map[string]any, diff controller.SentinelDiff, logger *logrus.Entry) (res ctrl.Result, err error)
+	DiffFunc      func(ctx context.Context, o client.Object, read controller.SentinelRead, data map[string]any, logger *logrus.Entry, ignoreDiff ...patch.CalculateOption) (diff controller.SentinelDiff, res ctrl.Result, err error)
+}
+
+// NewMockSentinelReconcilerAction is the default constructor of MockSentinelReconcilerAction
+func NewMockSentinelReconcilerAction(reconciler controller.SentinelReconcilerAction) *MockSentinelReconcilerAction {
+	if reconciler == nil {
+		panic("reconciler can't be nil")
+	}
+
+	return &MockSentinelReconcilerAction{
+		ActionRecorder: NewActionRecorder(),
+		reconciler:     reconciler,
+	}
+}
+
+func (h *MockSentinelReconcilerAction) Configure(ctx context.Context, req ctrl.Request, o client.Object, data map[string]any, logger *logrus.Entry) (res ctrl.Result, err error) {
+	if injection := h.record(&ActionCall{Hook: HookConfigure, Object: o}); injection != nil {
+		return injection.result, injection.err
+	}
+	if h.ConfigureFunc != nil {
+		return h.ConfigureFunc(ctx, req, o, data, logger)
+	}
+
+	return h.reconciler.Configure(ctx, req, o, data, logger)
+}
+
+func (h *MockSentinelReconcilerAction) Read(ctx context.Context, o client.Object, data map[string]any, logger *logrus.Entry) (read controller.SentinelRead, res ctrl.Result, err error) {
+	if injection := h.record(&ActionCall{Hook: HookRead, Object: o}); injection != nil {
+		return controller.NewBasicSentinelRead(), injection.result, injection.err
+	}
+	if h.ReadFunc != nil {
+		return h.ReadFunc(ctx, o, data, logger)
+	}
+
+	return h.reconciler.Read(ctx, o, data, logger)
+}
+
+func (h *MockSentinelReconcilerAction) Create(ctx context.Context, o client.Object, data map[string]any, objects []client.Object, logger *logrus.Entry) (res ctrl.Result, err error) {
+	if injection := h.record(&ActionCall{Hook: HookCreate, Object: o, Objects: objects}); injection != nil {
+		return injection.result, injection.err
+	}
+	if h.CreateFunc != nil {
+		return h.CreateFunc(ctx, o, data, objects, logger)
+	}
+
+	return h.reconciler.Create(ctx, o, data, objects, logger)
+}
+
+func (h *MockSentinelReconcilerAction) Update(ctx context.Context, o client.Object, data map[string]any, objects []client.Object, logger *logrus.Entry) (res ctrl.Result, err error) {
+	if injection := h.record(&ActionCall{Hook: HookUpdate, Object: o, Objects: objects}); injection != nil {
+		return injection.result, injection.err
+	}
+	if h.UpdateFunc != nil {
+		return h.UpdateFunc(ctx, o, data, objects, logger)
+	}
+
+	return h.reconciler.Update(ctx, o, data, objects, logger)
+}
+
+func (h *MockSentinelReconcilerAction) Delete(ctx context.Context, o client.Object, data map[string]any, objects []client.Object, logger *logrus.Entry) (err error) {
+	if injection := h.record(&ActionCall{Hook: HookDelete, Object: o, Objects: objects}); injection != nil {
+		return injection.err
+	}
+	if h.DeleteFunc != nil {
+		return h.DeleteFunc(ctx, o, data, objects, logger)
+	}
+
+	return h.reconciler.Delete(ctx, o, data, objects, logger)
+}
+
+func (h *MockSentinelReconcilerAction) Finalize(ctx context.Context, o client.Object, data map[string]any, read controller.SentinelRead, logger *logrus.Entry) (err error) {
+	if injection := h.record(&ActionCall{Hook: HookFinalize, Object: o, Read: read}); injection != nil {
+		return injection.err
+	}
+	if h.FinalizeFunc != nil {
+		return h.FinalizeFunc(ctx, o, data, read, logger)
+	}
+
+	if finalizerAction, ok := h.reconciler.(controller.SentinelFinalizerAction); ok {
+		return finalizerAction.Finalize(ctx, o, data, read, logger)
+	}
+
+	return nil
+}
+
+func (h *MockSentinelReconcilerAction) OnError(ctx context.Context, o client.Object, data map[string]any, currentErr error, logger *logrus.Entry) (res ctrl.Result, err error) {
+	if injection := h.record(&ActionCall{Hook: HookOnError, Object: o, Error: currentErr}); injection != nil {
+		return injection.result, injection.err
+	}
+	if h.OnErrorFunc != nil {
+		return h.OnErrorFunc(ctx, o, data, currentErr, logger)
+	}
+
+	return h.reconciler.OnError(ctx, o, data, currentErr, logger)
+}
+
+func (h *MockSentinelReconcilerAction) OnSuccess(ctx context.Context, o client.Object, data map[string]any, diff controller.SentinelDiff, logger *logrus.Entry) (res ctrl.Result, err error) {
+	if injection := h.record(&ActionCall{Hook: HookOnSuccess, Object: o, Diff: diff}); injection != nil {
+		return injection.result, injection.err
+	}
+	if h.OnSuccessFunc != nil {
+		return h.OnSuccessFunc(ctx, o, data, diff, logger)
+	}
+
+	return h.reconciler.OnSuccess(ctx, o, data, diff, logger)
+}
+
+func (h *MockSentinelReconcilerAction) Diff(ctx context.Context, o client.Object, read controller.SentinelRead, data map[string]any, logger *logrus.Entry, ignoreDiff ...patch.CalculateOption) (diff controller.SentinelDiff, res ctrl.Result, err error) {
+	call := &ActionCall{Hook: HookDiff, Object: o, Read: read}
+	if injection := h.record(call); injection != nil {
+		return controller.NewBasicSentinelDiff(), injection.result, injection.err
+	}
+	if h.DiffFunc != nil {
+		diff, res, err = h.DiffFunc(ctx, o, read, data, logger, ignoreDiff...)
+	} else {
+		diff, res, err = h.reconciler.Diff(ctx, o, read, data, logger, ignoreDiff...)
+	}
+	h.setDiff(call, diff)
+
+	return diff, res, err
+}
+
+func (h *MockSentinelReconcilerAction) GetIgnoresDiff() []patch.CalculateOption {
+	return h.reconciler.GetIgnoresDiff()
+}
+
+func (h *MockSentinelReconcilerAction) Client() client.Client {
+	return h.reconciler.Client()
+}
+
+func (h *MockSentinelReconcilerAction) Recorder() record.EventRecorder {
+	return h.reconciler.Recorder()
+}
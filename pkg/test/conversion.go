@@ -0,0 +1,127 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/apis"
+	"github.com/google/go-cmp/cmp"
+	fuzz "github.com/google/gofuzz"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+const (
+	// DefaultConversionFuzzIterations is the default number of objects fuzzed on each conversion way
+	DefaultConversionFuzzIterations = 100
+)
+
+// FuzzConversionOptions is the options of FuzzConversion
+type FuzzConversionOptions struct {
+	// Iterations is the number of objects fuzzed on each conversion way
+	// Default to DefaultConversionFuzzIterations
+	Iterations int
+
+	// Seed is the seed of fuzzer. It is random when 0
+	Seed int64
+
+	// Funcs is the custom fuzz functions, like 'func(o *MyType, c fuzz.Continue)'
+	// Use it to fill fields that gofuzz can't handle, or to keep only the values supported by all versions
+	Funcs []any
+}
+
+// FuzzConversion permit to check that the conversions spoke -> hub -> spoke and hub -> spoke -> hub not lost data
+// The fields of hub that not exist on spoke need to be stored with apis.MarshalData on ConvertFrom, and restored with apis.UnmarshalData on ConvertTo
+func FuzzConversion(t *testing.T, hub conversion.Hub, spoke conversion.Convertible, opts *FuzzConversionOptions) {
+	if opts == nil {
+		opts = &FuzzConversionOptions{}
+	}
+	iterations := opts.Iterations
+	if iterations <= 0 {
+		iterations = DefaultConversionFuzzIterations
+	}
+	f := NewConversionFuzzer(opts.Seed, opts.Funcs...)
+
+	t.Run("spoke-hub-spoke", func(t *testing.T) {
+		for i := 0; i < iterations; i++ {
+			spokeBefore := spoke.DeepCopyObject().(conversion.Convertible)
+			f.Fuzz(spokeBefore)
+
+			hubCopy := hub.DeepCopyObject().(conversion.Hub)
+			if err := spokeBefore.DeepCopyObject().(conversion.Convertible).ConvertTo(hubCopy); err != nil {
+				t.Fatalf("Error when convert spoke %T to hub %T: %s", spokeBefore, hubCopy, err.Error())
+			}
+			spokeAfter := spoke.DeepCopyObject().(conversion.Convertible)
+			if err := spokeAfter.ConvertFrom(hubCopy); err != nil {
+				t.Fatalf("Error when convert hub %T to spoke %T: %s", hubCopy, spokeAfter, err.Error())
+			}
+			removeConversionData(t, spokeAfter)
+
+			if !apiequality.Semantic.DeepEqual(spokeBefore, spokeAfter) {
+				t.Fatalf("Spoke %T change after conversion (-before, +after):\n%s", spokeBefore, cmp.Diff(spokeBefore, spokeAfter))
+			}
+		}
+	})
+
+	t.Run("hub-spoke-hub", func(t *testing.T) {
+		for i := 0; i < iterations; i++ {
+			hubBefore := hub.DeepCopyObject().(conversion.Hub)
+			f.Fuzz(hubBefore)
+
+			spokeCopy := spoke.DeepCopyObject().(conversion.Convertible)
+			if err := spokeCopy.ConvertFrom(hubBefore.DeepCopyObject().(conversion.Hub)); err != nil {
+				t.Fatalf("Error when convert hub %T to spoke %T: %s", hubBefore, spokeCopy, err.Error())
+			}
+			hubAfter := hub.DeepCopyObject().(conversion.Hub)
+			if err := spokeCopy.ConvertTo(hubAfter); err != nil {
+				t.Fatalf("Error when convert spoke %T to hub %T: %s", spokeCopy, hubAfter, err.Error())
+			}
+			removeConversionData(t, hubAfter)
+
+			if !apiequality.Semantic.DeepEqual(hubBefore, hubAfter) {
+				t.Fatalf("Hub %T change after conversion (-before, +after):\n%s", hubBefore, cmp.Diff(hubBefore, hubAfter))
+			}
+		}
+	})
+}
+
+// NewConversionFuzzer permit to get fuzzer for conversion tests
+// The type meta is not fuzzed, because of it's set by conversion, and the metadata keep only fields that can be copied between versions
+func NewConversionFuzzer(seed int64, funcs ...any) *fuzz.Fuzzer {
+	f := fuzz.New().NilChance(0.2).NumElements(1, 5)
+	if seed != 0 {
+		f = fuzz.NewWithSeed(seed).NilChance(0.2).NumElements(1, 5)
+	}
+
+	defaultFuncs := []any{
+		func(o *metav1.TypeMeta, c fuzz.Continue) {
+			o.APIVersion = ""
+			o.Kind = ""
+		},
+		func(o *metav1.ObjectMeta, c fuzz.Continue) {
+			c.Fuzz(&o.Name)
+			c.Fuzz(&o.Namespace)
+			c.Fuzz(&o.Labels)
+			c.Fuzz(&o.Annotations)
+			c.Fuzz(&o.Generation)
+		},
+	}
+
+	return f.Funcs(append(defaultFuncs, funcs...)...)
+}
+
+// removeConversionData permit to remove the annotation set by apis.MarshalData
+func removeConversionData(t *testing.T, o any) {
+	accessor, err := meta.Accessor(o)
+	if err != nil {
+		t.Fatalf("Error when get metadata of %T: %s", o, err.Error())
+	}
+
+	annotations := accessor.GetAnnotations()
+	delete(annotations, apis.ConversionDataAnnotation)
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	accessor.SetAnnotations(annotations)
+}
@@ -0,0 +1,162 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/apis"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// testRemoteHub is the hub version, it add the field Spec.Replicas
+type testRemoteHub struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              testRemoteHubSpec            `json:"spec,omitempty"`
+	Status            apis.BasicRemoteObjectStatus `json:"status,omitempty"`
+}
+
+type testRemoteHubSpec struct {
+	Name     string            `json:"name,omitempty"`
+	Replicas int32             `json:"replicas,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+}
+
+func (h *testRemoteHub) Hub() {}
+
+func (h *testRemoteHub) DeepCopyObject() runtime.Object {
+	o := &testRemoteHub{TypeMeta: h.TypeMeta, Spec: h.Spec}
+	h.ObjectMeta.DeepCopyInto(&o.ObjectMeta)
+	h.Status.DeepCopyInto(&o.Status)
+	if h.Spec.Labels != nil {
+		o.Spec.Labels = make(map[string]string, len(h.Spec.Labels))
+		for key, value := range h.Spec.Labels {
+			o.Spec.Labels[key] = value
+		}
+	}
+	return o
+}
+
+// testRemoteSpoke is the old version, without the field Spec.Replicas
+type testRemoteSpoke struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              testRemoteSpokeSpec          `json:"spec,omitempty"`
+	Status            apis.BasicRemoteObjectStatus `json:"status,omitempty"`
+}
+
+type testRemoteSpokeSpec struct {
+	Name   string            `json:"name,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+func (h *testRemoteSpoke) DeepCopyObject() runtime.Object {
+	o := &testRemoteSpoke{TypeMeta: h.TypeMeta, Spec: h.Spec}
+	h.ObjectMeta.DeepCopyInto(&o.ObjectMeta)
+	h.Status.DeepCopyInto(&o.Status)
+	if h.Spec.Labels != nil {
+		o.Spec.Labels = make(map[string]string, len(h.Spec.Labels))
+		for key, value := range h.Spec.Labels {
+			o.Spec.Labels[key] = value
+		}
+	}
+	return o
+}
+
+func (h *testRemoteSpoke) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*testRemoteHub)
+
+	restored := &testRemoteHub{}
+	isRestored, err := apis.UnmarshalData(h, restored)
+	if err != nil {
+		return err
+	}
+
+	dst.ObjectMeta = h.ObjectMeta
+	dst.Spec.Name = h.Spec.Name
+	dst.Spec.Labels = h.Spec.Labels
+	dst.Status = h.Status
+	if isRestored {
+		dst.Spec.Replicas = restored.Spec.Replicas
+	}
+
+	return nil
+}
+
+func (h *testRemoteSpoke) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*testRemoteHub)
+
+	h.ObjectMeta = src.ObjectMeta
+	h.Spec.Name = src.Spec.Name
+	h.Spec.Labels = src.Spec.Labels
+	h.Status = src.Status
+
+	return apis.MarshalData(src, h)
+}
+
+// testMultiPhaseHub is the hub version, the field Spec.Size replace Spec.Replicas
+type testMultiPhaseHub struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              testMultiPhaseHubSpec            `json:"spec,omitempty"`
+	Status            apis.BasicMultiPhaseObjectStatus `json:"status,omitempty"`
+}
+
+type testMultiPhaseHubSpec struct {
+	Size int64 `json:"size,omitempty"`
+}
+
+func (h *testMultiPhaseHub) Hub() {}
+
+func (h *testMultiPhaseHub) DeepCopyObject() runtime.Object {
+	o := &testMultiPhaseHub{TypeMeta: h.TypeMeta, Spec: h.Spec}
+	h.ObjectMeta.DeepCopyInto(&o.ObjectMeta)
+	h.Status.DeepCopyInto(&o.Status)
+	return o
+}
+
+// testMultiPhaseSpoke is the old version, the field Spec.Replicas is renamed on hub
+type testMultiPhaseSpoke struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              testMultiPhaseSpokeSpec          `json:"spec,omitempty"`
+	Status            apis.BasicMultiPhaseObjectStatus `json:"status,omitempty"`
+}
+
+type testMultiPhaseSpokeSpec struct {
+	Replicas int64 `json:"replicas,omitempty"`
+}
+
+func (h *testMultiPhaseSpoke) DeepCopyObject() runtime.Object {
+	o := &testMultiPhaseSpoke{TypeMeta: h.TypeMeta, Spec: h.Spec}
+	h.ObjectMeta.DeepCopyInto(&o.ObjectMeta)
+	h.Status.DeepCopyInto(&o.Status)
+	return o
+}
+
+func (h *testMultiPhaseSpoke) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*testMultiPhaseHub)
+	dst.ObjectMeta = h.ObjectMeta
+	dst.Spec.Size = h.Spec.Replicas
+	dst.Status = h.Status
+
+	return nil
+}
+
+func (h *testMultiPhaseSpoke) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*testMultiPhaseHub)
+	h.ObjectMeta = src.ObjectMeta
+	h.Spec.Replicas = src.Spec.Size
+	h.Status = src.Status
+
+	return nil
+}
+
+func TestFuzzConversion(t *testing.T) {
+	// With BasicRemoteObjectStatus and field only on hub
+	FuzzConversion(t, &testRemoteHub{}, &testRemoteSpoke{}, nil)
+
+	// With BasicMultiPhaseObjectStatus and renamed field
+	FuzzConversion(t, &testMultiPhaseHub{}, &testMultiPhaseSpoke{}, &FuzzConversionOptions{Iterations: 10, Seed: 1})
+}
@@ -0,0 +1,244 @@
+package test
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/object"
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// UpdateGoldenEnvVar is the environment variable to update the golden files instead to compare them, like `UPDATE_GOLDEN=1 go test ./...`
+	UpdateGoldenEnvVar = "UPDATE_GOLDEN"
+
+	// UpdateGoldenFlag is the flag of test binary to update the golden files, like `go test ./... -update`
+	// The library not define it, because of a package level flag is registered on all test binaries that import it. It is used when the tests define it with flag.Bool
+	UpdateGoldenFlag = "update"
+
+	// goldenFileExtension is the extension of golden files
+	goldenFileExtension = ".yaml"
+)
+
+// IsUpdateGolden permit to know if the golden files need to be updated
+// It read the flag UpdateGoldenFlag when the test binary define it, and the environment variable UpdateGoldenEnvVar
+func IsUpdateGolden() bool {
+	if f := flag.Lookup(UpdateGoldenFlag); f != nil {
+		if isUpdate, _ := strconv.ParseBool(f.Value.String()); isUpdate {
+			return true
+		}
+	}
+
+	value, found := os.LookupEnv(UpdateGoldenEnvVar)
+	if !found {
+		return false
+	}
+	isUpdate, _ := strconv.ParseBool(value)
+
+	return isUpdate
+}
+
+// ObjectFromYamlFile permit to read object fixture from YAML file
+func ObjectFromYamlFile(file string, o client.Object) (err error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return errors.Wrapf(err, "Error when read file %s", file)
+	}
+	if err = yaml.UnmarshalStrict(b, o); err != nil {
+		return errors.Wrapf(err, "Error when unmarshall file %s", file)
+	}
+
+	return nil
+}
+
+// GoldenStepRead permit to run 'read' from step action, and to compare the expected objects with the golden files on directory
+// The golden files are updated when run tests with the environment variable UpdateGoldenEnvVar, or with the flag UpdateGoldenFlag if defined
+func GoldenStepRead(t *testing.T, goldenDir string, action controller.MultiPhaseStepReconcilerAction, o object.MultiPhaseObject, data map[string]any, s *runtime.Scheme) {
+	t.Helper()
+
+	if data == nil {
+		data = map[string]any{}
+	}
+	read, _, err := action.Read(context.Background(), o, data, logrus.NewEntry(logrus.StandardLogger()))
+	if err != nil {
+		t.Fatalf("Error when call 'read' from step action %s: %s", action.GetPhaseName().String(), err.Error())
+	}
+
+	GoldenObjects(t, goldenDir, read.GetExpectedObjects(), s)
+}
+
+// GoldenObjects permit to compare the objects with the golden files on directory, one YAML file per object
+// The golden files are updated when run tests with the environment variable UpdateGoldenEnvVar, or with the flag UpdateGoldenFlag if defined
+func GoldenObjects(t *testing.T, goldenDir string, objects []client.Object, s *runtime.Scheme) {
+	t.Helper()
+
+	if IsUpdateGolden() {
+		if err := updateGoldenFiles(goldenDir, objects, s); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	diffs, err := compareGoldenFiles(goldenDir, objects, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, diff := range diffs {
+		t.Error(diff)
+	}
+}
+
+// updateGoldenFiles permit to write the objects on golden directory, and to remove the golden files of objects that not exist anymore
+func updateGoldenFiles(goldenDir string, objects []client.Object, s *runtime.Scheme) (err error) {
+	currentFiles, err := marshalGoldenObjects(objects, s)
+	if err != nil {
+		return err
+	}
+	goldenFiles, err := readGoldenFiles(goldenDir)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(goldenDir, 0755); err != nil {
+		return errors.Wrapf(err, "Error when create golden directory %s", goldenDir)
+	}
+	for fileName := range goldenFiles {
+		if _, isFound := currentFiles[fileName]; !isFound {
+			if err = os.Remove(filepath.Join(goldenDir, fileName)); err != nil {
+				return errors.Wrapf(err, "Error when remove golden file %s", fileName)
+			}
+		}
+	}
+	for fileName, content := range currentFiles {
+		if err = os.WriteFile(filepath.Join(goldenDir, fileName), []byte(content), 0644); err != nil {
+			return errors.Wrapf(err, "Error when write golden file %s", fileName)
+		}
+	}
+
+	return nil
+}
+
+// compareGoldenFiles permit to compare the objects with the golden files
+// It return one readable diff per golden file that not match
+func compareGoldenFiles(goldenDir string, objects []client.Object, s *runtime.Scheme) (diffs []string, err error) {
+	currentFiles, err := marshalGoldenObjects(objects, s)
+	if err != nil {
+		return nil, err
+	}
+	goldenFiles, err := readGoldenFiles(goldenDir)
+	if err != nil {
+		return nil, err
+	}
+
+	fileNames := make([]string, 0, len(currentFiles)+len(goldenFiles))
+	for fileName := range currentFiles {
+		fileNames = append(fileNames, fileName)
+	}
+	for fileName := range goldenFiles {
+		if _, isFound := currentFiles[fileName]; !isFound {
+			fileNames = append(fileNames, fileName)
+		}
+	}
+	sort.Strings(fileNames)
+
+	diffs = make([]string, 0)
+	for _, fileName := range fileNames {
+		current, isCurrentFound := currentFiles[fileName]
+		golden, isGoldenFound := goldenFiles[fileName]
+		switch {
+		case !isGoldenFound:
+			diffs = append(diffs, fmt.Sprintf("Golden file %s not exist, run tests with %s=1 to create it", filepath.Join(goldenDir, fileName), UpdateGoldenEnvVar))
+		case !isCurrentFound:
+			diffs = append(diffs, fmt.Sprintf("Golden file %s has no object, run tests with %s=1 to remove it", filepath.Join(goldenDir, fileName), UpdateGoldenEnvVar))
+		default:
+			if diff := cmp.Diff(strings.Split(golden, "\n"), strings.Split(current, "\n")); diff != "" {
+				diffs = append(diffs, fmt.Sprintf("Golden file %s not match (-golden +current), run tests with %s=1 to update it:\n%s", filepath.Join(goldenDir, fileName), UpdateGoldenEnvVar, diff))
+			}
+		}
+	}
+
+	return diffs, nil
+}
+
+// readGoldenFiles permit to read the golden files from directory, by file name
+func readGoldenFiles(goldenDir string) (goldenFiles map[string]string, err error) {
+	goldenFiles = map[string]string{}
+
+	entries, err := os.ReadDir(goldenDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return goldenFiles, nil
+		}
+		return nil, errors.Wrapf(err, "Error when read golden directory %s", goldenDir)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != goldenFileExtension {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(goldenDir, entry.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error when read golden file %s", entry.Name())
+		}
+		goldenFiles[entry.Name()] = string(b)
+	}
+
+	return goldenFiles, nil
+}
+
+// marshalGoldenObjects permit to convert objects on YAML, by golden file name
+// The file name is kind_namespace_name.yaml, or kind_name.yaml for cluster scoped object
+func marshalGoldenObjects(objects []client.Object, s *runtime.Scheme) (goldenFiles map[string]string, err error) {
+	goldenFiles = make(map[string]string, len(objects))
+
+	for _, o := range objects {
+		gvk, err := apiutil.GVKForObject(o, s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error when get GVK of object %s", o.GetName())
+		}
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error when convert object %s to unstructured", o.GetName())
+		}
+		u := &unstructured.Unstructured{Object: content}
+		u.SetGroupVersionKind(gvk)
+
+		// Remove the fields that not set by builders
+		unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+		if status, isFound := u.Object["status"].(map[string]any); isFound && len(status) == 0 {
+			delete(u.Object, "status")
+		}
+
+		b, err := yaml.Marshal(u.Object)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error when marshall object %s to YAML", o.GetName())
+		}
+
+		parts := []string{strings.ToLower(gvk.Kind)}
+		if o.GetNamespace() != "" {
+			parts = append(parts, o.GetNamespace())
+		}
+		parts = append(parts, o.GetName())
+		fileName := strings.Join(parts, "_") + goldenFileExtension
+		if _, isFound := goldenFiles[fileName]; isFound {
+			return nil, errors.Errorf("Object %s is duplicated", fileName)
+		}
+		goldenFiles[fileName] = string(b)
+	}
+
+	return goldenFiles, nil
+}
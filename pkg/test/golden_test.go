@@ -0,0 +1,98 @@
+package test
+
+import (
+	"flag"
+	"path/filepath"
+	"testing"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestGoldenStepRead(t *testing.T) {
+	o := &testMultiPhaseObject{}
+	assert.NoError(t, ObjectFromYamlFile("testdata/golden/parent.yaml", o))
+	assert.Error(t, ObjectFromYamlFile("testdata/golden/not-exist.yaml", o))
+
+	c := NewFakeClient(nil)
+	action := &testConfigMapStepAction{
+		MultiPhaseStepReconcilerAction: controller.NewBasicMultiPhaseStepReconcilerAction(c, "ConfigMap", "ConfigMapReady", record.NewFakeRecorder(10)),
+	}
+
+	GoldenStepRead(t, "testdata/golden/configmap", action, o, nil, scheme.Scheme)
+}
+
+func TestGoldenFiles(t *testing.T) {
+	goldenDir := filepath.Join(t.TempDir(), "golden")
+	objects := []client.Object{
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}, Data: map[string]string{"foo": "bar"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test"}},
+	}
+
+	// When golden files not exist
+	diffs, err := compareGoldenFiles(goldenDir, objects, scheme.Scheme)
+	assert.NoError(t, err)
+	assert.Len(t, diffs, 2)
+
+	// When update golden files
+	assert.NoError(t, updateGoldenFiles(goldenDir, objects, scheme.Scheme))
+	goldenFiles, err := readGoldenFiles(goldenDir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"configmap_default_test.yaml": "apiVersion: v1\ndata:\n  foo: bar\nkind: ConfigMap\nmetadata:\n  name: test\n  namespace: default\n",
+		"namespace_test.yaml":         "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: test\nspec: {}\n",
+	}, goldenFiles)
+	diffs, err = compareGoldenFiles(goldenDir, objects, scheme.Scheme)
+	assert.NoError(t, err)
+	assert.Empty(t, diffs)
+
+	// When object change
+	objects[0].(*corev1.ConfigMap).Data["foo"] = "baz"
+	diffs, err = compareGoldenFiles(goldenDir, objects, scheme.Scheme)
+	assert.NoError(t, err)
+	if assert.Len(t, diffs, 1) {
+		assert.Contains(t, diffs[0], "configmap_default_test.yaml")
+		assert.Contains(t, diffs[0], `"  foo: bar"`)
+		assert.Contains(t, diffs[0], `"  foo: baz"`)
+	}
+
+	// When object not exist anymore
+	diffs, err = compareGoldenFiles(goldenDir, objects[:1], scheme.Scheme)
+	assert.NoError(t, err)
+	assert.Len(t, diffs, 2)
+	assert.NoError(t, updateGoldenFiles(goldenDir, objects[:1], scheme.Scheme))
+	goldenFiles, err = readGoldenFiles(goldenDir)
+	assert.NoError(t, err)
+	assert.Len(t, goldenFiles, 1)
+
+	// When objects are duplicated
+	_, err = compareGoldenFiles(goldenDir, []client.Object{objects[0], objects[0]}, scheme.Scheme)
+	assert.Error(t, err)
+}
+
+// updateGolden is defined like on the tests that use the golden files with '-update'
+var updateGolden = flag.Bool(UpdateGoldenFlag, false, "update the golden files")
+
+func TestIsUpdateGolden(t *testing.T) {
+	isUpdate := *updateGolden
+	defer func() { *updateGolden = isUpdate }()
+	*updateGolden = false
+
+	t.Setenv(UpdateGoldenEnvVar, "")
+	assert.False(t, IsUpdateGolden())
+
+	t.Setenv(UpdateGoldenEnvVar, "1")
+	assert.True(t, IsUpdateGolden())
+
+	t.Setenv(UpdateGoldenEnvVar, "false")
+	assert.False(t, IsUpdateGolden())
+
+	// When flag is set
+	assert.NoError(t, flag.Set(UpdateGoldenFlag, "true"))
+	assert.True(t, IsUpdateGolden())
+}
@@ -0,0 +1,276 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/disaster37/operator-sdk-extra/pkg/apis/shared"
+	"github.com/disaster37/operator-sdk-extra/pkg/object"
+	"github.com/stretchr/testify/assert"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultMaxIterations is the default number of reconcile loops run by RunUntilSteady before to fail
+const DefaultMaxIterations = 10
+
+// ReconcilerHarness permit to run the reconcilers against fake client and fake recorder, without API server
+// It drive the reconcile loops deterministically and offer assertions on objects, events, conditions and results
+type ReconcilerHarness struct {
+	t             *testing.T
+	client        client.Client
+	recorder      *record.FakeRecorder
+	reconciler    reconcile.Reconciler
+	maxIterations int
+	events        []string
+	result        ctrl.Result
+	err           error
+}
+
+// NewFakeClient permit to get fake client with the objects and the status subresource enabled for them
+// The scheme can be nil to use the client-go scheme. All kinds of scheme are known by the REST mapper, as namespaced except the core cluster scoped kinds
+func NewFakeClient(s *runtime.Scheme, objects ...client.Object) client.WithWatch {
+	if s == nil {
+		s = scheme.Scheme
+	}
+
+	return fake.NewClientBuilder().
+		WithScheme(s).
+		WithRESTMapper(testrestmapper.TestOnlyStaticRESTMapper(s)).
+		WithObjects(objects...).
+		WithStatusSubresource(objects...).
+		Build()
+}
+
+// NewReconcilerHarness is the default constructor of ReconcilerHarness
+// Use reconcile.Func to test reconciler that need more parameters, like the multi phase reconciler
+// Set the reconcile delay of reconciler to 0 with SetReconcileDelay, so the reconcile loops run without waiting
+func NewReconcilerHarness(t *testing.T, c client.Client, recorder *record.FakeRecorder, reconciler reconcile.Reconciler) *ReconcilerHarness {
+	if c == nil {
+		panic("client can't be nil")
+	}
+	if recorder == nil {
+		panic("recorder can't be nil")
+	}
+	if reconciler == nil {
+		panic("reconciler can't be nil")
+	}
+
+	return &ReconcilerHarness{
+		t:             t,
+		client:        c,
+		recorder:      recorder,
+		reconciler:    reconciler,
+		maxIterations: DefaultMaxIterations,
+		events:        make([]string, 0),
+	}
+}
+
+// WithMaxIterations permit to set the maximum number of reconcile loops run by RunUntilSteady
+func (h *ReconcilerHarness) WithMaxIterations(maxIterations int) *ReconcilerHarness {
+	h.maxIterations = maxIterations
+	return h
+}
+
+// Client permit to get the fake client
+func (h *ReconcilerHarness) Client() client.Client {
+	return h.client
+}
+
+// Events permit to get all events emitted since the harness is created
+func (h *ReconcilerHarness) Events() []string {
+	return h.events
+}
+
+// Result permit to get the result and error of the last reconcile loop
+func (h *ReconcilerHarness) Result() (res ctrl.Result, err error) {
+	return h.result, h.err
+}
+
+// Reconcile permit to run one reconcile loop on object
+func (h *ReconcilerHarness) Reconcile(key types.NamespacedName) (res ctrl.Result, err error) {
+	h.result, h.err = h.reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	h.drainEvents()
+
+	return h.result, h.err
+}
+
+// RunUntilSteady permit to run reconcile loops until the object reach steady state
+// The object is steady when the reconcile not ask to requeue immediately and not change the object (like status or finalizer), or when the object is deleted
+// It not take care about RequeueAfter, and stop on the first error. The object is only used to read it
+func (h *ReconcilerHarness) RunUntilSteady(key types.NamespacedName, o client.Object) (iterations int, err error) {
+	for iterations = 1; iterations <= h.maxIterations; iterations++ {
+		resourceVersion, isFound, err := h.getResourceVersion(key, o)
+		if err != nil {
+			return iterations, err
+		}
+		if !isFound {
+			return iterations - 1, nil
+		}
+
+		res, err := h.Reconcile(key)
+		if err != nil {
+			return iterations, err
+		}
+
+		newResourceVersion, isFound, err := h.getResourceVersion(key, o)
+		if err != nil {
+			return iterations, err
+		}
+		if !isFound || (!res.Requeue && newResourceVersion == resourceVersion) {
+			return iterations, nil
+		}
+	}
+
+	return h.maxIterations, errors.Errorf("Object %s not steady after %d reconcile loops", key.String(), h.maxIterations)
+}
+
+// AssertSteady permit to run reconcile loops until steady state, and fail the test if not reached
+func (h *ReconcilerHarness) AssertSteady(key types.NamespacedName, o client.Object) bool {
+	h.t.Helper()
+
+	_, err := h.RunUntilSteady(key, o)
+	return assert.NoError(h.t, err)
+}
+
+// AssertResult permit to check the result of the last reconcile loop
+func (h *ReconcilerHarness) AssertResult(expected ctrl.Result) bool {
+	h.t.Helper()
+
+	return assert.Equal(h.t, expected, h.result)
+}
+
+// AssertRequeue permit to check that the last reconcile loop ask to requeue, immediately or later
+func (h *ReconcilerHarness) AssertRequeue() bool {
+	h.t.Helper()
+
+	return assert.True(h.t, h.result.Requeue || h.result.RequeueAfter > 0, "Expected requeue, but get %+v", h.result)
+}
+
+// AssertEvents permit to check all events emitted since the harness is created, in order
+// The events are formated by the fake recorder like 'Normal Reason Message'
+func (h *ReconcilerHarness) AssertEvents(expected ...string) bool {
+	h.t.Helper()
+
+	if expected == nil {
+		expected = []string{}
+	}
+	return assert.Equal(h.t, expected, h.events)
+}
+
+// AssertEventsContain permit to check that some events are emitted since the harness is created
+func (h *ReconcilerHarness) AssertEventsContain(expected ...string) bool {
+	h.t.Helper()
+
+	return assert.Subset(h.t, h.events, expected)
+}
+
+// AssertObject permit to check that the object exist, and to run checks on it
+// The check function can be nil
+func (h *ReconcilerHarness) AssertObject(key types.NamespacedName, o client.Object, check func(t *testing.T, o client.Object)) bool {
+	h.t.Helper()
+
+	if !assert.NoError(h.t, h.client.Get(context.Background(), key, o), "Object %T %s not found", o, key.String()) {
+		return false
+	}
+	if check != nil {
+		check(h.t, o)
+	}
+
+	return true
+}
+
+// AssertObjectNotFound permit to check that the object not exist
+func (h *ReconcilerHarness) AssertObjectNotFound(key types.NamespacedName, o client.Object) bool {
+	h.t.Helper()
+
+	err := h.client.Get(context.Background(), key, o)
+	return assert.True(h.t, k8serrors.IsNotFound(err), "Expected object %T %s not found, but get error: %v", o, key.String(), err)
+}
+
+// AssertCondition permit to check the status of condition on object
+// The object need to be MultiPhaseObject, RemoteObject or unstructured
+func (h *ReconcilerHarness) AssertCondition(key types.NamespacedName, o client.Object, conditionName shared.ConditionName, status metav1.ConditionStatus) bool {
+	h.t.Helper()
+
+	if !h.AssertObject(key, o, nil) {
+		return false
+	}
+	conditions, err := getConditions(o)
+	if !assert.NoError(h.t, err) {
+		return false
+	}
+	condition := meta.FindStatusCondition(conditions, conditionName.String())
+	if !assert.NotNil(h.t, condition, "Condition %s not found on object %s", conditionName.String(), key.String()) {
+		return false
+	}
+
+	return assert.Equal(h.t, status, condition.Status, "Condition %s: %s", conditionName.String(), condition.Message)
+}
+
+// drainEvents permit to read the events emitted by the fake recorder
+func (h *ReconcilerHarness) drainEvents() {
+	for {
+		select {
+		case event := <-h.recorder.Events:
+			h.events = append(h.events, event)
+		default:
+			return
+		}
+	}
+}
+
+// getResourceVersion permit to read the resource version of object
+func (h *ReconcilerHarness) getResourceVersion(key types.NamespacedName, o client.Object) (resourceVersion string, isFound bool, err error) {
+	current := o.DeepCopyObject().(client.Object)
+	if err = h.client.Get(context.Background(), key, current); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "", false, nil
+		}
+		return "", false, errors.Wrapf(err, "Error when get object %s", key.String())
+	}
+
+	return current.GetResourceVersion(), true, nil
+}
+
+// getConditions permit to get the conditions from object status
+func getConditions(o client.Object) (conditions []metav1.Condition, err error) {
+	switch t := o.(type) {
+	case object.MultiPhaseObject:
+		return t.GetStatus().GetConditions(), nil
+	case object.RemoteObject:
+		return t.GetStatus().GetConditions(), nil
+	case *unstructured.Unstructured:
+		rawConditions, _, err := unstructured.NestedSlice(t.Object, "status", "conditions")
+		if err != nil {
+			return nil, errors.Wrap(err, "Error when read conditions from unstructured object")
+		}
+		conditions = make([]metav1.Condition, 0, len(rawConditions))
+		for _, rawCondition := range rawConditions {
+			rawConditionMap, ok := rawCondition.(map[string]any)
+			if !ok {
+				return nil, errors.Errorf("Condition has wrong type %T", rawCondition)
+			}
+			condition := metav1.Condition{}
+			if err = runtime.DefaultUnstructuredConverter.FromUnstructured(rawConditionMap, &condition); err != nil {
+				return nil, errors.Wrap(err, "Error when convert condition from unstructured")
+			}
+			conditions = append(conditions, condition)
+		}
+		return conditions, nil
+	default:
+		return nil, errors.Errorf("Object of type %T has not conditions, it need to be MultiPhaseObject, RemoteObject or unstructured", o)
+	}
+}
@@ -0,0 +1,181 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/apis"
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/object"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type testMultiPhaseObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              testMultiPhaseSpec               `json:"spec,omitempty"`
+	Status            apis.BasicMultiPhaseObjectStatus `json:"status,omitempty"`
+}
+
+type testMultiPhaseSpec struct {
+	Data string `json:"data,omitempty"`
+}
+
+func (h *testMultiPhaseObject) DeepCopyObject() runtime.Object {
+	o := &testMultiPhaseObject{TypeMeta: h.TypeMeta, Spec: h.Spec}
+	h.ObjectMeta.DeepCopyInto(&o.ObjectMeta)
+	h.Status.DeepCopyInto(&o.Status)
+	return o
+}
+
+func (h *testMultiPhaseObject) GetStatus() object.MultiPhaseObjectStatus { return &h.Status }
+
+type testConfigMapStepAction struct {
+	controller.MultiPhaseStepReconcilerAction
+}
+
+func (h *testConfigMapStepAction) Read(ctx context.Context, o object.MultiPhaseObject, data map[string]any, logger *logrus.Entry) (read controller.MultiPhaseRead, res ctrl.Result, err error) {
+	read = controller.NewBasicMultiPhaseRead()
+	mo := o.(*testMultiPhaseObject)
+
+	cm := &corev1.ConfigMap{}
+	if err = h.Client().Get(ctx, types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}, cm); err == nil {
+		read.SetCurrentObjects([]client.Object{cm})
+	} else if client.IgnoreNotFound(err) != nil {
+		return nil, res, err
+	}
+
+	read.SetExpectedObjects([]client.Object{&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: o.GetName(), Namespace: o.GetNamespace()},
+		Data:       map[string]string{"data": mo.Spec.Data},
+	}})
+
+	return read, res, nil
+}
+
+// testOtherNamespaceStepAction create a ConfigMap on another namespace, so it is linked to its parent with tracking labels
+type testOtherNamespaceStepAction struct {
+	controller.MultiPhaseStepReconcilerAction
+}
+
+func (h *testOtherNamespaceStepAction) Read(ctx context.Context, o object.MultiPhaseObject, data map[string]any, logger *logrus.Entry) (read controller.MultiPhaseRead, res ctrl.Result, err error) {
+	read = controller.NewBasicMultiPhaseRead()
+
+	cm := &corev1.ConfigMap{}
+	if err = h.Client().Get(ctx, types.NamespacedName{Namespace: "other", Name: o.GetName()}, cm); err == nil {
+		read.SetCurrentObjects([]client.Object{cm})
+	} else if client.IgnoreNotFound(err) != nil {
+		return nil, res, err
+	}
+
+	read.SetExpectedObjects([]client.Object{&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: o.GetName(), Namespace: "other"},
+	}})
+
+	return read, res, nil
+}
+
+func testMultiPhaseScheme(t *testing.T) *runtime.Scheme {
+	s := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(s))
+	s.AddKnownTypeWithName(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "TestMultiPhase"}, &testMultiPhaseObject{})
+
+	return s
+}
+
+func newTestMultiPhaseHarness(t *testing.T, objects ...client.Object) *ReconcilerHarness {
+	c := NewFakeClient(testMultiPhaseScheme(t), objects...)
+	recorder := record.NewFakeRecorder(100)
+
+	reconciler := controller.NewBasicMultiPhaseReconciler(c, "test", "test.example.com/finalizer", logrus.NewEntry(logrus.New()), recorder)
+	reconciler.SetReconcileDelay(0)
+	action := controller.NewBasicMultiPhaseReconcilerAction(c, "Ready", recorder)
+	stepAction := &testConfigMapStepAction{
+		MultiPhaseStepReconcilerAction: controller.NewBasicMultiPhaseStepReconcilerAction(c, "ConfigMap", "ConfigMapReady", recorder),
+	}
+	otherNamespaceStepAction := &testOtherNamespaceStepAction{
+		MultiPhaseStepReconcilerAction: controller.NewBasicMultiPhaseStepReconcilerAction(c, "OtherNamespace", "OtherNamespaceReady", recorder),
+	}
+	otherNamespaceStepAction.MultiPhaseStepReconcilerAction.(*controller.BasicMultiPhaseStepReconcilerAction).SetTrackedChildrenLists(&corev1.ConfigMapList{})
+
+	return NewReconcilerHarness(t, c, recorder, reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		return reconciler.Reconcile(ctx, req, &testMultiPhaseObject{}, map[string]any{}, action, stepAction, otherNamespaceStepAction)
+	}))
+}
+
+func TestReconcilerHarness(t *testing.T) {
+	o := &testMultiPhaseObject{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", UID: "test-uid"},
+		Spec:       testMultiPhaseSpec{Data: "foo"},
+	}
+	key := client.ObjectKeyFromObject(o)
+	otherKey := types.NamespacedName{Namespace: "other", Name: "test"}
+	h := newTestMultiPhaseHarness(t, o)
+
+	// When create
+	iterations, err := h.RunUntilSteady(key, &testMultiPhaseObject{})
+	assert.NoError(t, err)
+	assert.Greater(t, iterations, 1)
+	h.AssertResult(ctrl.Result{})
+	h.AssertObject(key, &corev1.ConfigMap{}, func(t *testing.T, o client.Object) {
+		assert.Equal(t, "foo", o.(*corev1.ConfigMap).Data["data"])
+	})
+	h.AssertObject(otherKey, &corev1.ConfigMap{}, func(t *testing.T, o client.Object) {
+		assert.Empty(t, o.GetOwnerReferences())
+		assert.Equal(t, "test-uid", o.GetLabels()[controller.ParentUIDLabel])
+	})
+	h.AssertCondition(key, &testMultiPhaseObject{}, "ConfigMapReady", metav1.ConditionTrue)
+	h.AssertCondition(key, &testMultiPhaseObject{}, "Ready", metav1.ConditionTrue)
+	h.AssertEvents("Normal CreateCompleted Object 'test' successfully created", "Normal CreateCompleted Object 'test' successfully created")
+
+	// When already steady
+	iterations, err = h.RunUntilSteady(key, &testMultiPhaseObject{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, iterations)
+
+	// When update
+	assert.NoError(t, h.Client().Get(context.Background(), key, o))
+	o.Spec.Data = "bar"
+	assert.NoError(t, h.Client().Update(context.Background(), o))
+	h.AssertSteady(key, &testMultiPhaseObject{})
+	h.AssertObject(key, &corev1.ConfigMap{}, func(t *testing.T, o client.Object) {
+		assert.Equal(t, "bar", o.(*corev1.ConfigMap).Data["data"])
+	})
+	h.AssertEventsContain("Normal UpdateCompleted Object 'test' successfully updated")
+
+	// When delete, the tracked children are deleted too
+	assert.NoError(t, h.Client().Delete(context.Background(), o))
+	h.AssertSteady(key, &testMultiPhaseObject{})
+	h.AssertObjectNotFound(key, &testMultiPhaseObject{})
+	h.AssertObjectNotFound(otherKey, &corev1.ConfigMap{})
+
+	// When object not exist
+	iterations, err = h.RunUntilSteady(key, &testMultiPhaseObject{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, iterations)
+}
+
+func TestReconcilerHarnessNotSteady(t *testing.T) {
+	o := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	c := NewFakeClient(nil, o)
+	h := NewReconcilerHarness(t, c, record.NewFakeRecorder(10), reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		return reconcile.Result{Requeue: true}, nil
+	})).WithMaxIterations(3)
+
+	iterations, err := h.RunUntilSteady(client.ObjectKeyFromObject(o), &corev1.ConfigMap{})
+	assert.Error(t, err)
+	assert.Equal(t, 3, iterations)
+	h.AssertRequeue()
+	h.AssertEvents()
+}
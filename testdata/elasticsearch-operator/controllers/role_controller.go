@@ -89,6 +89,10 @@ func (r *RoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *RoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := controller.ValidateStatusAccessors(&elasticsearchapicrd.Role{}); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&elasticsearchapicrd.Role{}).
 		Complete(r)
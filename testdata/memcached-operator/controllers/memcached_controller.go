@@ -106,6 +106,10 @@ func (r *MemcachedReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *MemcachedReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := controller.ValidateStatusAccessors(&cachecrd.Memcached{}, &appv1.Deployment{}, &corev1.ConfigMap{}); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&cachecrd.Memcached{}).
 		Owns(&appv1.Deployment{}).